@@ -2,6 +2,7 @@ package paypal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -10,8 +11,13 @@ import (
 // GetAuthorization returns an authorization by ID
 // Endpoint: GET /v2/payments/authorization/ID
 func (c *Client) GetAuthorization(authID string) (*Authorization, error) {
+	return c.GetAuthorizationContext(context.Background(), authID)
+}
+
+// GetAuthorizationContext is like GetAuthorization but uses ctx for the request
+func (c *Client) GetAuthorizationContext(ctx context.Context, authID string) (*Authorization, error) {
 	buf := bytes.NewBuffer([]byte(""))
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s%s", c.APIBase, "/v2/payments/authorizations/", authID), buf)
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s%s", c.APIBase, "/v2/payments/authorizations/", authID), buf)
 	auth := &Authorization{}
 
 	if err != nil {
@@ -25,16 +31,22 @@ func (c *Client) GetAuthorization(authID string) (*Authorization, error) {
 // CaptureAuthorization captures and process an existing authorization.
 // To use this method, the original payment must have Intent set to "authorize"
 // Endpoint: POST /v2/payments/authorizations/ID/capture
-func (c *Client) CaptureAuthorization(authID string, paymentCaptureRequest *PaymentCaptureRequest) (*PaymentCaptureResponse, error) {
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/authorizations/"+authID+"/capture"), paymentCaptureRequest)
-	paymentCaptureResponse := &PaymentCaptureResponse{}
+func (c *Client) CaptureAuthorization(authID string, paymentCaptureRequest *PaymentCaptureRequest, opts ...RequestOption) (*PaymentCaptureResponse, error) {
+	return c.CaptureAuthorizationContext(context.Background(), authID, paymentCaptureRequest, opts...)
+}
 
-	req.Header.Set(HeaderPrefer, HeaderPreferRepresentation)
+// CaptureAuthorizationContext is like CaptureAuthorization but uses ctx for the request
+func (c *Client) CaptureAuthorizationContext(ctx context.Context, authID string, paymentCaptureRequest *PaymentCaptureRequest, opts ...RequestOption) (*PaymentCaptureResponse, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/authorizations/"+authID+"/capture"), paymentCaptureRequest)
+	paymentCaptureResponse := &PaymentCaptureResponse{}
 
 	if err != nil {
 		return paymentCaptureResponse, err
 	}
 
+	req.Header.Set(HeaderPrefer, HeaderPreferRepresentation)
+	c.applyIdempotency(req, opts)
+
 	err = c.SendWithAuth(req, paymentCaptureResponse)
 	return paymentCaptureResponse, err
 }
@@ -42,8 +54,13 @@ func (c *Client) CaptureAuthorization(authID string, paymentCaptureRequest *Paym
 // VoidAuthorization voids a previously authorized payment
 // Endpoint: POST /v2/payments/authorization/ID/void
 func (c *Client) VoidAuthorization(authID string) error {
+	return c.VoidAuthorizationContext(context.Background(), authID)
+}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/authorizations/"+authID+"/void"), nil)
+// VoidAuthorizationContext is like VoidAuthorization but uses ctx for the request
+func (c *Client) VoidAuthorizationContext(ctx context.Context, authID string) error {
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/authorizations/"+authID+"/void"), nil)
 
 	if err != nil {
 		return err
@@ -57,6 +74,11 @@ func (c *Client) VoidAuthorization(authID string) error {
 // PayPal recommends to reauthorize payment after ~3 days
 // Endpoint: POST /v2/payments/authorization/ID/reauthorize
 func (c *Client) ReauthorizeAuthorization(authID string, a *Amount) (*Authorization, error) {
+	return c.ReauthorizeAuthorizationContext(context.Background(), authID, a)
+}
+
+// ReauthorizeAuthorizationContext is like ReauthorizeAuthorization but uses ctx for the request
+func (c *Client) ReauthorizeAuthorizationContext(ctx context.Context, authID string, a *Amount) (*Authorization, error) {
 	// buf := bytes.NewBuffer([]byte(`{"amount":{"currenct_":"` + a.Currency + `","total":"` + a.Total + `"}}`))
 	body, berr := json.Marshal(&struct {
 		Amount *Amount `json:"amount,omitempty"`
@@ -65,7 +87,7 @@ func (c *Client) ReauthorizeAuthorization(authID string, a *Amount) (*Authorizat
 		return nil, berr
 	}
 	buf := bytes.NewBuffer(body)
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/authorizations/"+authID+"/reauthorize"), buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/authorizations/"+authID+"/reauthorize"), buf)
 	auth := &Authorization{}
 
 	if err != nil {
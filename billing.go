@@ -2,6 +2,7 @@ package paypal
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -45,12 +46,36 @@ type (
 		TotalPages string        `json:"total_pages,omitempty"`
 		Links      []Link        `json:"links,omitempty"`
 	}
+
+	// BillingAgreementTransaction is a payment or a status change of a billing agreement
+	BillingAgreementTransaction struct {
+		TransactionID   string        `json:"transaction_id"`
+		Status          string        `json:"status"`
+		TransactionType string        `json:"transaction_type"`
+		Amount          *AmountPayout `json:"amount,omitempty"`
+		FeeAmount       *AmountPayout `json:"fee_amount,omitempty"`
+		NetAmount       *AmountPayout `json:"net_amount,omitempty"`
+		PayerEmail      string        `json:"payer_email,omitempty"`
+		PayerName       string        `json:"payer_name,omitempty"`
+		TimeStamp       PTime         `json:"time_stamp"`
+		TimeZone        string        `json:"time_zone,omitempty"`
+	}
+
+	// BillingAgreementTransactionsResp GET /v1/payments/billing-agreements/ID/transactions
+	BillingAgreementTransactionsResp struct {
+		Transactions []BillingAgreementTransaction `json:"agreement_transaction_list"`
+	}
 )
 
 // CreateBillingPlan creates a billing plan in Paypal
-// Endpoint: POST /v2/payments/billing-plans
+// Endpoint: POST /v1/payments/billing-plans
 func (c *Client) CreateBillingPlan(plan BillingPlan) (*CreateBillingResp, error) {
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/billing-plans"), plan)
+	return c.CreateBillingPlanContext(context.Background(), plan)
+}
+
+// CreateBillingPlanContext is like CreateBillingPlan but uses ctx for the request
+func (c *Client) CreateBillingPlanContext(ctx context.Context, plan BillingPlan) (*CreateBillingResp, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-plans"), plan)
 	response := &CreateBillingResp{}
 	if err != nil {
 		return response, err
@@ -61,27 +86,35 @@ func (c *Client) CreateBillingPlan(plan BillingPlan) (*CreateBillingResp, error)
 
 // ActivatePlan activates a billing plan
 // By default, a new plan is not activated
-// Endpoint: PATCH /v2/payments/billing-plans/
+// Endpoint: PATCH /v1/payments/billing-plans/
 func (c *Client) ActivatePlan(planID string) error {
+	return c.ActivatePlanContext(context.Background(), planID)
+}
+
+// ActivatePlanContext is like ActivatePlan but uses ctx for the request
+func (c *Client) ActivatePlanContext(ctx context.Context, planID string) error {
 	buf := bytes.NewBuffer([]byte(`[{"op":"replace","path":"/","value":{"state":"ACTIVE"}}]`))
-	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/billing-plans/"+planID), buf)
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-plans/"+planID), buf)
 	if err != nil {
 		return err
 	}
-	req.SetBasicAuth(c.ClientID, c.Secret)
-	req.Header.Set("Authorization", "Bearer "+c.Token.Token)
 	return c.SendWithAuth(req, nil)
 }
 
 // CreateBillingAgreement creates an agreement for specified plan
-// Endpoint: POST /v2/payments/billing-agreements
+// Endpoint: POST /v1/payments/billing-agreements
 func (c *Client) CreateBillingAgreement(a BillingAgreement) (*CreateAgreementResp, error) {
+	return c.CreateBillingAgreementContext(context.Background(), a)
+}
+
+// CreateBillingAgreementContext is like CreateBillingAgreement but uses ctx for the request
+func (c *Client) CreateBillingAgreementContext(ctx context.Context, a BillingAgreement) (*CreateAgreementResp, error) {
 	// PayPal needs only ID, so we will remove all fields except Plan ID
 	a.Plan = BillingPlan{
 		ID: a.Plan.ID,
 	}
 
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/billing-agreements"), a)
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-agreements"), a)
 	response := &CreateAgreementResp{}
 	if err != nil {
 		return response, err
@@ -91,18 +124,20 @@ func (c *Client) CreateBillingAgreement(a BillingAgreement) (*CreateAgreementRes
 }
 
 // ExecuteApprovedAgreement - Use this call to execute (complete) a PayPal agreement that has been approved by the payer.
-// Endpoint: POST /v2/payments/billing-agreements/token/agreement-execute
+// Endpoint: POST /v1/payments/billing-agreements/token/agreement-execute
 func (c *Client) ExecuteApprovedAgreement(token string) (*ExecuteAgreementResponse, error) {
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/billing-agreements/"+token+"/agreement-execute"), nil)
+	return c.ExecuteApprovedAgreementContext(context.Background(), token)
+}
+
+// ExecuteApprovedAgreementContext is like ExecuteApprovedAgreement but uses ctx for the request
+func (c *Client) ExecuteApprovedAgreementContext(ctx context.Context, token string) (*ExecuteAgreementResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-agreements/"+token+"/agreement-execute"), nil)
 	response := &ExecuteAgreementResponse{}
 
 	if err != nil {
 		return response, err
 	}
 
-	req.SetBasicAuth(c.ClientID, c.Secret)
-	req.Header.Set("Authorization", "Bearer "+c.Token.Token)
-
 	if err = c.SendWithAuth(req, response); err != nil {
 		return response, err
 	}
@@ -115,19 +150,181 @@ func (c *Client) ExecuteApprovedAgreement(token string) (*ExecuteAgreementRespon
 }
 
 // ListBillingPlans lists billing-plans
-// Endpoint: GET /v2/payments/billing-plans
+// Endpoint: GET /v1/payments/billing-plans
 func (c *Client) ListBillingPlans(bplp BillingPlanListParams) (*BillingPlanListResp, error) {
-	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/billing-plans"), nil)
+	return c.ListBillingPlansContext(context.Background(), bplp)
+}
+
+// ListBillingPlansContext is like ListBillingPlans but uses ctx for the request
+func (c *Client) ListBillingPlansContext(ctx context.Context, bplp BillingPlanListParams) (*BillingPlanListResp, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-plans"), nil)
+	response := &BillingPlanListResp{}
+	if err != nil {
+		return response, err
+	}
 	q := req.URL.Query()
 	q.Add("page", bplp.Page)
 	q.Add("page_size", bplp.PageSize)
 	q.Add("status", bplp.Status)
 	q.Add("total_required", bplp.TotalRequired)
 	req.URL.RawQuery = q.Encode()
-	response := &BillingPlanListResp{}
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// GetBillingPlan shows details for a billing plan, by ID
+// Endpoint: GET /v1/payments/billing-plans/ID
+func (c *Client) GetBillingPlan(planID string) (*BillingPlan, error) {
+	return c.GetBillingPlanContext(context.Background(), planID)
+}
+
+// GetBillingPlanContext is like GetBillingPlan but uses ctx for the request
+func (c *Client) GetBillingPlanContext(ctx context.Context, planID string) (*BillingPlan, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-plans/"+planID), nil)
+	response := &BillingPlan{}
+	if err != nil {
+		return response, err
+	}
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// UpdateBillingPlan updates a billing plan, by ID. Only the replace operation is supported,
+// e.g. {Operation: "replace", Path: "/merchant-preferences", Value: map[string]string{"max_fail_attempts": "3"}}
+// Endpoint: PATCH /v1/payments/billing-plans/ID
+func (c *Client) UpdateBillingPlan(planID string, patches []PaymentPatch) error {
+	return c.UpdateBillingPlanContext(context.Background(), planID, patches)
+}
+
+// UpdateBillingPlanContext is like UpdateBillingPlan but uses ctx for the request
+func (c *Client) UpdateBillingPlanContext(ctx context.Context, planID string, patches []PaymentPatch) error {
+	req, err := c.NewRequestContext(ctx, "PATCH", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-plans/"+planID), patches)
+	if err != nil {
+		return err
+	}
+	return c.SendWithAuth(req, nil)
+}
+
+// GetBillingAgreement shows details for a billing agreement, by ID. Its AgreementDetails
+// report the outstanding balance and the failed payments
+// Endpoint: GET /v1/payments/billing-agreements/ID
+func (c *Client) GetBillingAgreement(agreementID string) (*ExecuteAgreementResponse, error) {
+	return c.GetBillingAgreementContext(context.Background(), agreementID)
+}
+
+// GetBillingAgreementContext is like GetBillingAgreement but uses ctx for the request
+func (c *Client) GetBillingAgreementContext(ctx context.Context, agreementID string) (*ExecuteAgreementResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-agreements/"+agreementID), nil)
+	response := &ExecuteAgreementResponse{}
+	if err != nil {
+		return response, err
+	}
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// SuspendBillingAgreement suspends an active billing agreement
+// Endpoint: POST /v1/payments/billing-agreements/ID/suspend
+func (c *Client) SuspendBillingAgreement(agreementID, note string) error {
+	return c.SuspendBillingAgreementContext(context.Background(), agreementID, note)
+}
+
+// SuspendBillingAgreementContext is like SuspendBillingAgreement but uses ctx for the request
+func (c *Client) SuspendBillingAgreementContext(ctx context.Context, agreementID, note string) error {
+	return c.agreementAction(ctx, agreementID, "suspend", note)
+}
+
+// ReactivateBillingAgreement reactivates a suspended billing agreement
+// Endpoint: POST /v1/payments/billing-agreements/ID/re-activate
+func (c *Client) ReactivateBillingAgreement(agreementID, note string) error {
+	return c.ReactivateBillingAgreementContext(context.Background(), agreementID, note)
+}
+
+// ReactivateBillingAgreementContext is like ReactivateBillingAgreement but uses ctx for the request
+func (c *Client) ReactivateBillingAgreementContext(ctx context.Context, agreementID, note string) error {
+	return c.agreementAction(ctx, agreementID, "re-activate", note)
+}
+
+// CancelBillingAgreement cancels a billing agreement for good
+// Endpoint: POST /v1/payments/billing-agreements/ID/cancel
+func (c *Client) CancelBillingAgreement(agreementID, note string) error {
+	return c.CancelBillingAgreementContext(context.Background(), agreementID, note)
+}
+
+// CancelBillingAgreementContext is like CancelBillingAgreement but uses ctx for the request
+func (c *Client) CancelBillingAgreementContext(ctx context.Context, agreementID, note string) error {
+	return c.agreementAction(ctx, agreementID, "cancel", note)
+}
+
+// SetBillingAgreementBalance sets the outstanding balance of a billing agreement,
+// it can only be lowered
+// Endpoint: POST /v1/payments/billing-agreements/ID/set-balance
+func (c *Client) SetBillingAgreementBalance(agreementID string, balance AmountPayout) error {
+	return c.SetBillingAgreementBalanceContext(context.Background(), agreementID, balance)
+}
+
+// SetBillingAgreementBalanceContext is like SetBillingAgreementBalance but uses ctx for the request
+func (c *Client) SetBillingAgreementBalanceContext(ctx context.Context, agreementID string, balance AmountPayout) error {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-agreements/"+agreementID+"/set-balance"), balance)
+	if err != nil {
+		return err
+	}
+	return c.SendWithAuth(req, nil)
+}
+
+// BillBillingAgreementBalance bills the outstanding balance of a billing agreement, up to amount
+// Endpoint: POST /v1/payments/billing-agreements/ID/bill-balance
+func (c *Client) BillBillingAgreementBalance(agreementID, note string, amount AmountPayout, opts ...RequestOption) error {
+	return c.BillBillingAgreementBalanceContext(context.Background(), agreementID, note, amount, opts...)
+}
+
+// BillBillingAgreementBalanceContext is like BillBillingAgreementBalance but uses ctx for the request
+func (c *Client) BillBillingAgreementBalanceContext(ctx context.Context, agreementID, note string, amount AmountPayout, opts ...RequestOption) error {
+	type billBalanceRequest struct {
+		Note   string       `json:"note"`
+		Amount AmountPayout `json:"amount"`
+	}
+
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-agreements/"+agreementID+"/bill-balance"), billBalanceRequest{Note: note, Amount: amount})
+	if err != nil {
+		return err
+	}
+	c.applyIdempotency(req, opts)
+
+	return c.SendWithAuth(req, nil)
+}
+
+// ListBillingAgreementTransactions lists the transactions of a billing agreement from
+// the day of start to the day of end, in UTC
+// Endpoint: GET /v1/payments/billing-agreements/ID/transactions
+func (c *Client) ListBillingAgreementTransactions(agreementID string, start, end time.Time) (*BillingAgreementTransactionsResp, error) {
+	return c.ListBillingAgreementTransactionsContext(context.Background(), agreementID, start, end)
+}
+
+// ListBillingAgreementTransactionsContext is like ListBillingAgreementTransactions but uses ctx for the request
+func (c *Client) ListBillingAgreementTransactionsContext(ctx context.Context, agreementID string, start, end time.Time) (*BillingAgreementTransactionsResp, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-agreements/"+agreementID+"/transactions"), nil)
+	response := &BillingAgreementTransactionsResp{}
 	if err != nil {
 		return response, err
 	}
+	q := req.URL.Query()
+	q.Set("start_date", start.UTC().Format("2006-01-02"))
+	q.Set("end_date", end.UTC().Format("2006-01-02"))
+	req.URL.RawQuery = q.Encode()
 	err = c.SendWithAuth(req, response)
 	return response, err
 }
+
+// agreementAction posts the note of a state change to a billing agreement
+func (c *Client) agreementAction(ctx context.Context, agreementID, action, note string) error {
+	type noteRequest struct {
+		Note string `json:"note"`
+	}
+
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/billing-agreements/"+agreementID+"/"+action), noteRequest{Note: note})
+	if err != nil {
+		return err
+	}
+	return c.SendWithAuth(req, nil)
+}
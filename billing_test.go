@@ -0,0 +1,155 @@
+package paypal
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBillingAgreementLifecycle(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+
+		switch r.URL.Path {
+		case "/v1/payments/billing-agreements/I-0LN988D3JACS":
+			w.Write([]byte(`{"id":"I-0LN988D3JACS","state":"Active","agreement_details":{"outstanding_balance":{"currency":"USD","value":"10.00"},"cycles_remaining":"11","cycles_completed":"1","failed_payment_count":"2"}}`))
+		case "/v1/payments/billing-agreements/I-0LN988D3JACS/transactions":
+			if q := r.URL.Query(); q.Get("start_date") != "2020-01-01" || q.Get("end_date") != "2020-02-01" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"agreement_transaction_list":[{"transaction_id":"I-0LN988D3JACS","status":"Created","transaction_type":"Recurring Payment","time_stamp":"2020-01-15T09:00:00Z","time_zone":"GMT"}]}`))
+		default:
+			if r.Header.Get(HeaderRequestID) != "" && r.URL.Path != "/v1/payments/billing-agreements/I-0LN988D3JACS/bill-balance" {
+				t.Errorf("unexpected request ID for %s", r.URL.Path)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	agreement, err := c.GetBillingAgreement("I-0LN988D3JACS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	details := agreement.AgreementDetails
+	if details.FailedPaymentCount != 2 || details.OutstandingBalance.Value != "10.00" || details.CyclesRemaining != 11 {
+		t.Fatalf("unexpected agreement %+v", agreement)
+	}
+
+	if err := c.SuspendBillingAgreement(agreement.ID, "Suspending"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReactivateBillingAgreement(agreement.ID, "Reactivating"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetBillingAgreementBalance(agreement.ID, AmountPayout{Currency: "USD", Value: "5.00"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BillBillingAgreementBalance(agreement.ID, "Billing", AmountPayout{Currency: "USD", Value: "5.00"}, WithRequestID("bill-1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CancelBillingAgreement(agreement.ID, "Cancelling"); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := c.ListBillingAgreementTransactions(agreement.ID, time.Date(2019, 12, 31, 23, 0, 0, 0, time.FixedZone("EST", -5*3600)), time.Date(2020, 2, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs.Transactions) != 1 || txs.Transactions[0].TransactionType != "Recurring Payment" || txs.Transactions[0].TimeStamp.IsZero() {
+		t.Fatalf("unexpected transactions %+v", txs)
+	}
+
+	expected := []string{
+		`POST /v1/payments/billing-agreements/I-0LN988D3JACS/suspend {"note":"Suspending"}`,
+		`POST /v1/payments/billing-agreements/I-0LN988D3JACS/re-activate {"note":"Reactivating"}`,
+		`POST /v1/payments/billing-agreements/I-0LN988D3JACS/set-balance {"currency":"USD","value":"5.00"}`,
+		`POST /v1/payments/billing-agreements/I-0LN988D3JACS/bill-balance {"note":"Billing","amount":{"currency":"USD","value":"5.00"}}`,
+		`POST /v1/payments/billing-agreements/I-0LN988D3JACS/cancel {"note":"Cancelling"}`,
+	}
+	for i, e := range expected {
+		if calls[i+1] != e {
+			t.Errorf("expected call %s, got %s", e, calls[i+1])
+		}
+	}
+}
+
+func TestGetAndUpdateBillingPlan(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/payments/billing-plans/P-7DC96732KA7763723UOPKETA" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Method == "PATCH" {
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != `[{"op":"replace","path":"/","value":{"description":"New description"}}]` {
+				t.Errorf("unexpected patch %s", body)
+			}
+			return
+		}
+		w.Write([]byte(`{"id":"P-7DC96732KA7763723UOPKETA","state":"ACTIVE","name":"Plan","payment_definitions":[{"type":"REGULAR","frequency":"Month"}]}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	plan, err := c.GetBillingPlan("P-7DC96732KA7763723UOPKETA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.State != "ACTIVE" || len(plan.PaymentDefinitions) != 1 {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+
+	err = c.UpdateBillingPlan(plan.ID, []PaymentPatch{{Operation: "replace", Path: "/", Value: map[string]string{"description": "New description"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBillingPaths(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"id":"I-0LN988D3JACS"}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	if _, err := c.CreateBillingPlan(BillingPlan{Name: "Plan"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ActivatePlan("P-7DC96732KA7763723UOPKETA"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateBillingAgreement(BillingAgreement{Plan: BillingPlan{ID: "P-7DC96732KA7763723UOPKETA"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ExecuteApprovedAgreement("EC-0JP008296V262500Y"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListBillingPlans(BillingPlanListParams{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"POST /v1/payments/billing-plans",
+		"PATCH /v1/payments/billing-plans/P-7DC96732KA7763723UOPKETA",
+		"POST /v1/payments/billing-agreements",
+		"POST /v1/payments/billing-agreements/EC-0JP008296V262500Y/agreement-execute",
+		"GET /v1/payments/billing-plans",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+}
@@ -1,6 +1,7 @@
 package paypal
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
@@ -23,14 +24,20 @@ curl -v -X POST https://api.sandbox.paypal.com/v2/payments/captures/2GG279541U47
 
 // RefundCapture - https://developer.paypal.com/docs/api/payments/v2/#captures_refund
 // Endpoint: POST /v2/payments/captures/ID/refund
-func (c *Client) RefundCapture(captureID string, request *RefundRequest) (*RefundResponse, error) {
+func (c *Client) RefundCapture(captureID string, request *RefundRequest, opts ...RequestOption) (*RefundResponse, error) {
+	return c.RefundCaptureContext(context.Background(), captureID, request, opts...)
+}
+
+// RefundCaptureContext is like RefundCapture but uses ctx for the request
+func (c *Client) RefundCaptureContext(ctx context.Context, captureID string, request *RefundRequest, opts ...RequestOption) (*RefundResponse, error) {
 	refund := new(RefundResponse)
 
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/captures/"+captureID+"/refund"), request)
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/captures/"+captureID+"/refund"), request)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set(HeaderPrefer, HeaderPreferRepresentation)
+	c.applyIdempotency(req, opts)
 	if err = c.SendWithAuth(req, refund); err != nil {
 		return nil, err
 	}
@@ -38,10 +45,60 @@ func (c *Client) RefundCapture(captureID string, request *RefundRequest) (*Refun
 	return refund, nil
 }
 
+// GetCapture returns a captured payment by ID
+// Endpoint: GET /v2/payments/captures/ID
+func (c *Client) GetCapture(captureID string) (*Capture, error) {
+	return c.GetCaptureContext(context.Background(), captureID)
+}
+
+// GetCaptureContext is like GetCapture but uses ctx for the request
+func (c *Client) GetCaptureContext(ctx context.Context, captureID string) (*Capture, error) {
+	capture := new(Capture)
+
+	req, err := c.NewRequestContext(ctx, http.MethodGet, fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/captures/"+captureID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.SendWithAuth(req, capture); err != nil {
+		return nil, err
+	}
+
+	return capture, nil
+}
+
+// GetRefund returns a refund of a captured payment by ID,
+// use GetSaleRefund for the refunds of v1 sales
+// Endpoint: GET /v2/payments/refunds/ID
+func (c *Client) GetRefund(refundID string) (*RefundResponse, error) {
+	return c.GetRefundContext(context.Background(), refundID)
+}
+
+// GetRefundContext is like GetRefund but uses ctx for the request
+func (c *Client) GetRefundContext(ctx context.Context, refundID string) (*RefundResponse, error) {
+	refund := new(RefundResponse)
+
+	req, err := c.NewRequestContext(ctx, http.MethodGet, fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/refunds/"+refundID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.SendWithAuth(req, refund); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// UpdateTracking adds or updates tracking information for PayPal transactions
+// Endpoint: POST /v1/shipping/trackers-batch
 func (c *Client) UpdateTracking(request *TrackersRequest) (*TrackersResponse, error) {
+	return c.UpdateTrackingContext(context.Background(), request)
+}
+
+// UpdateTrackingContext is like UpdateTracking but uses ctx for the request
+func (c *Client) UpdateTrackingContext(ctx context.Context, request *TrackersRequest) (*TrackersResponse, error) {
 	response := new(TrackersResponse)
 
-	req, err := c.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", c.APIBase, "/v1/shipping/trackers-batch"), request)
+	req, err := c.NewRequestContext(ctx, http.MethodPost, fmt.Sprintf("%s%s", c.APIBase, "/v1/shipping/trackers-batch"), request)
 	if err != nil {
 		return nil, err
 	}
@@ -2,13 +2,12 @@ package paypal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
-	"net/http/httputil"
 	"time"
 )
 
@@ -31,8 +30,28 @@ func NewClient(clientID string, secret string, APIBase string) (*Client, error)
 // No need to call SetAccessToken to apply new access token for current Client
 // Endpoint: POST /v1/oauth2/token
 func (c *Client) GetAccessToken() (*TokenResponse, error) {
+	return c.GetAccessTokenContext(context.Background())
+}
+
+// GetAccessTokenContext is like GetAccessToken but uses ctx for the token request
+func (c *Client) GetAccessTokenContext(ctx context.Context) (*TokenResponse, error) {
+	response, err := c.fetchToken(ctx)
+
+	// Set Token fur current Client
+	if response.Token != "" {
+		c.Lock()
+		c.Token = response
+		c.tokenExpiresAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+		c.Unlock()
+	}
+
+	return response, err
+}
+
+// fetchToken requests a new access token with the client ID and secret
+func (c *Client) fetchToken(ctx context.Context) (*TokenResponse, error) {
 	buf := bytes.NewBuffer([]byte("grant_type=client_credentials"))
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/oauth2/token"), buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/oauth2/token"), buf)
 	if err != nil {
 		return &TokenResponse{}, err
 	}
@@ -40,15 +59,14 @@ func (c *Client) GetAccessToken() (*TokenResponse, error) {
 	req.Header.Set("Content-type", "application/x-www-form-urlencoded")
 
 	response := &TokenResponse{}
-	err = c.SendWithBasicAuth(req, response)
-
-	// Set Token fur current Client
-	if response.Token != "" {
-		c.Token = response
-		c.tokenExpiresAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	if err = c.SendWithBasicAuth(req, response); err != nil {
+		return response, err
+	}
+	if response.Token == "" {
+		return response, errEmptyToken
 	}
 
-	return response, err
+	return response, nil
 }
 
 // SetHTTPClient sets *http.Client to current client
@@ -64,6 +82,12 @@ func (c *Client) SetAccessToken(token string) {
 	c.tokenExpiresAt = time.Time{}
 }
 
+// SetRetryPolicy sets the policy used by Send to retry transient failures.
+// Passing nil disables retries, which is the default
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.retryPolicy = policy
+}
+
 // SetLog will set/change the output destination.
 // If log file is set paypal will log all requests and responses to this Writer
 func (c *Client) SetLog(log io.Writer) {
@@ -73,13 +97,8 @@ func (c *Client) SetLog(log io.Writer) {
 // Send makes a request to the API, the response body will be
 // unmarshaled into v, or if v is an io.Writer, the response will
 // be written to it without decoding
+// The request is bound to the context of req, see SendContext
 func (c *Client) Send(req *http.Request, v interface{}) error {
-	var (
-		err  error
-		resp *http.Response
-		data []byte
-	)
-
 	// Set default headers
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Accept-Language", "en_US")
@@ -89,24 +108,13 @@ func (c *Client) Send(req *http.Request, v interface{}) error {
 		req.Header.Set("Content-type", "application/json")
 	}
 
-	resp, err = c.Client.Do(req)
-	c.log(req, resp)
-
+	resp, err := c.handler()(req)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		errResp := &ErrorResponse{Response: resp}
-		data, err = ioutil.ReadAll(resp.Body)
-
-		if err == nil && len(data) > 0 {
-			json.Unmarshal(data, errResp)
-		}
-
-		return errResp
-	}
 	if v == nil {
 		return nil
 	}
@@ -119,36 +127,158 @@ func (c *Client) Send(req *http.Request, v interface{}) error {
 	return json.NewDecoder(resp.Body).Decode(v)
 }
 
+// SendContext is like Send but binds req to ctx, so cancelling ctx aborts the request
+func (c *Client) SendContext(ctx context.Context, req *http.Request, v interface{}) error {
+	return c.Send(req.WithContext(ctx), v)
+}
+
 // SendWithAuth makes a request to the API and apply OAuth2 header automatically.
 // If the access token soon to be expired or already expired, it will try to get a new one before
 // making the main request
-// client.Token will be updated when changed
+// client.Token will be updated when changed, unless tokens come from a TokenSource.
+// When PayPal answers 401, e.g. invalid_token, the token is renewed and the request
+// replayed once, provided its body can be rewound
 func (c *Client) SendWithAuth(req *http.Request, v interface{}) error {
+	c.applyHeaders(req)
+
+	t, err := c.currentToken(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.AccessToken)
+
+	err = c.Send(req, v)
+	if !errors.Is(err, ErrAuthenticationFailure) {
+		return err
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return err
+	}
+
+	renewed, rerr := c.renewToken(req.Context(), t.AccessToken)
+	if rerr != nil || renewed.AccessToken == t.AccessToken {
+		return err
+	}
+	if req.GetBody != nil {
+		body, berr := req.GetBody()
+		if berr != nil {
+			return err
+		}
+		req.Body = body
+	}
+	req.Header.Set("Authorization", "Bearer "+renewed.AccessToken)
+
+	return c.Send(req, v)
+}
+
+// currentToken returns the token to authorize requests with, from the token source
+// or from c.Token, which is refreshed when it is about to expire
+func (c *Client) currentToken(ctx context.Context) (*Token, error) {
+	c.Lock()
+	ts := c.tokenSource
+	c.Unlock()
+	if ts != nil {
+		return ts.Token(ctx)
+	}
+
+	c.Lock()
+	t := c.token()
+	renewing := c.renewal != nil
+	c.Unlock()
+	if t != nil && (t.Expiry.IsZero() || time.Until(t.Expiry) >= RequestNewTokenBeforeExpiresIn) {
+		return t, nil
+	}
+	// Keep using the token while another caller renews it, as long as it is valid
+	if t != nil && renewing && time.Now().Before(t.Expiry) {
+		return t, nil
+	}
+
+	return c.fetchOwnToken(ctx)
+}
+
+// renewToken returns a new token replacing stale. Clients sharing a token
+// source only renew it once, the others get the token which replaced stale
+func (c *Client) renewToken(ctx context.Context, stale string) (*Token, error) {
+	c.Lock()
+	ts := c.tokenSource
+	c.Unlock()
+	if r, ok := ts.(tokenRenewer); ok {
+		return r.renew(ctx, stale)
+	}
+	if ts != nil {
+		return ts.Token(ctx)
+	}
+
+	c.Lock()
+	t := c.token()
+	c.Unlock()
+	if t != nil && t.AccessToken != stale {
+		return t, nil
+	}
+
+	return c.fetchOwnToken(ctx)
+}
+
+// token returns c.Token, or nil if not set. c must be locked
+func (c *Client) token() *Token {
+	if c.Token == nil {
+		return nil
+	}
+	return &Token{AccessToken: c.Token.Token, Type: c.Token.Type, Expiry: c.tokenExpiresAt}
+}
 
-	cerr := func() error {
+// fetchOwnToken requests a new c.Token, or waits for the request in progress.
+// c is only locked to swap the token, so requests keep using the current one meanwhile
+func (c *Client) fetchOwnToken(ctx context.Context) (*Token, error) {
+	for {
 		c.Lock()
-		defer c.Unlock()
-		if c.Token != nil {
-			if !c.tokenExpiresAt.IsZero() && c.tokenExpiresAt.Sub(time.Now()) < RequestNewTokenBeforeExpiresIn {
-				// c.Token will be updated in GetAccessToken call
-				if _, err := c.GetAccessToken(); err != nil {
-					return err
+		f := c.renewal
+		leader := f == nil
+		if leader {
+			f = &tokenFlight{done: make(chan struct{})}
+			c.renewal = f
+		}
+		c.Unlock()
+
+		if !leader {
+			select {
+			case <-f.done:
+				// The leader gave up, e.g. its request was cancelled, renew with ctx instead
+				if ctx.Err() == nil && (errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) {
+					continue
 				}
-			}
-		} else if c.Token == nil {
-			// c.Token will be updated in GetAccessToken call
-			if _, err := c.GetAccessToken(); err != nil {
-				return err
+				return f.token, f.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
 			}
 		}
-		req.Header.Set("Authorization", "Bearer "+c.Token.Token)
 
-		return nil
-	}()
-	if cerr != nil {
-		return cerr
+		// Waiters get errRefreshPanicked if fetchToken does not return
+		f.err = errRefreshPanicked
+		func() {
+			var response *TokenResponse
+			defer func() {
+				c.Lock()
+				if f.err == nil {
+					c.Token = response
+					c.tokenExpiresAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+					f.token = c.token()
+				}
+				c.renewal = nil
+				c.Unlock()
+				close(f.done)
+			}()
+			response, f.err = c.fetchToken(ctx)
+		}()
+
+		return f.token, f.err
 	}
-	return c.Send(req, v)
+}
+
+// SendWithAuthContext is like SendWithAuth but binds req to ctx,
+// including the token refresh that may happen before the main request
+func (c *Client) SendWithAuthContext(ctx context.Context, req *http.Request, v interface{}) error {
+	return c.SendWithAuth(req.WithContext(ctx), v)
 }
 
 // SendWithBasicAuth makes a request to the API using clientID:secret basic auth
@@ -158,9 +288,20 @@ func (c *Client) SendWithBasicAuth(req *http.Request, v interface{}) error {
 	return c.Send(req, v)
 }
 
+// SendWithBasicAuthContext is like SendWithBasicAuth but binds req to ctx
+func (c *Client) SendWithBasicAuthContext(ctx context.Context, req *http.Request, v interface{}) error {
+	return c.SendWithBasicAuth(req.WithContext(ctx), v)
+}
+
 // NewRequest constructs a request
 // Convert payload to a JSON
 func (c *Client) NewRequest(method, url string, payload interface{}) (*http.Request, error) {
+	return c.NewRequestContext(context.Background(), method, url, payload)
+}
+
+// NewRequestContext constructs a request bound to ctx
+// Convert payload to a JSON
+func (c *Client) NewRequestContext(ctx context.Context, method, url string, payload interface{}) (*http.Request, error) {
 	var buf io.Reader
 	if payload != nil {
 		b, err := json.Marshal(&payload)
@@ -169,25 +310,5 @@ func (c *Client) NewRequest(method, url string, payload interface{}) (*http.Requ
 		}
 		buf = bytes.NewBuffer(b)
 	}
-	return http.NewRequest(method, url, buf)
-}
-
-// log will dump request and response to the log file
-func (c *Client) log(r *http.Request, resp *http.Response) {
-
-	var (
-		reqDump  []byte
-		respDump []byte
-	)
-
-	if c.Log != nil {
-		if r != nil {
-			reqDump, _ = httputil.DumpRequestOut(r, true)
-			c.Log.Write([]byte(fmt.Sprintf("\nRequest: %s\n", reqDump)))
-		}
-		if resp != nil {
-			respDump, _ = httputil.DumpResponse(resp, true)
-			c.Log.Write([]byte(fmt.Sprintf("\nRequest: %s\n", respDump)))
-		}
-	}
+	return http.NewRequestWithContext(ctx, method, url, buf)
 }
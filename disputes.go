@@ -0,0 +1,331 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type EvidenceType string
+
+// Possible values for `evidence_type` in Evidence
+//
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-evidence
+const (
+	EvidenceTypeProofOfFulfillment       EvidenceType = "PROOF_OF_FULFILLMENT"
+	EvidenceTypeProofOfRefund            EvidenceType = "PROOF_OF_REFUND"
+	EvidenceTypeProofOfDeliverySignature EvidenceType = "PROOF_OF_DELIVERY_SIGNATURE"
+	EvidenceTypeProofOfReceiptCopy       EvidenceType = "PROOF_OF_RECEIPT_COPY"
+	EvidenceTypeReturnPolicy             EvidenceType = "RETURN_POLICY"
+	EvidenceTypeBillingAgreement         EvidenceType = "BILLING_AGREEMENT"
+	EvidenceTypeProofOfReshipment        EvidenceType = "PROOF_OF_RESHIPMENT"
+	EvidenceTypeItemDescription          EvidenceType = "ITEM_DESCRIPTION"
+	EvidenceTypePaidWithOtherMethod      EvidenceType = "PAID_WITH_OTHER_METHOD"
+	EvidenceTypeCopyOfContract           EvidenceType = "COPY_OF_CONTRACT"
+	EvidenceTypeProofOfReturn            EvidenceType = "PROOF_OF_RETURN"
+	EvidenceTypeOther                    EvidenceType = "OTHER"
+)
+
+type DisputeOfferType string
+
+// Possible values for `offer_type` in MakeOfferRequest
+//
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes-actions_make-offer
+const (
+	DisputeOfferTypeRefund                   DisputeOfferType = "REFUND"
+	DisputeOfferTypeRefundWithReturn         DisputeOfferType = "REFUND_WITH_RETURN"
+	DisputeOfferTypeRefundWithReplacement    DisputeOfferType = "REFUND_WITH_REPLACEMENT"
+	DisputeOfferTypeReplacementWithoutRefund DisputeOfferType = "REPLACEMENT_WITHOUT_REFUND"
+)
+
+type (
+	// ListDisputesParams filters and paginates ListDisputes.
+	// Zero values are not sent
+	ListDisputesParams struct {
+		StartTime             time.Time
+		DisputedTransactionID string
+		DisputeState          string // REQUIRED_ACTION, REQUIRED_OTHER_PARTY_ACTION, UNDER_PAYPAL_REVIEW, RESOLVED, OPEN_INQUIRIES, APPEALABLE
+		UpdateTimeBefore      time.Time
+		UpdateTimeAfter       time.Time
+		PageSize              int
+		NextPageToken         string
+	}
+
+	// ListDisputesResponse GET /v1/customer/disputes
+	ListDisputesResponse struct {
+		Items []Dispute `json:"items"`
+		Links []Link    `json:"links"`
+	}
+
+	// DisputeActionResponse is returned by the dispute actions, links point to the dispute
+	DisputeActionResponse struct {
+		Links []Link `json:"links"`
+	}
+
+	// AcceptClaimRequest - https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes-actions_accept-claim
+	AcceptClaimRequest struct {
+		Note                  string                         `json:"note"`
+		AcceptClaimReason     string                         `json:"accept_claim_reason,omitempty"`
+		InvoiceID             string                         `json:"invoice_id,omitempty"`
+		ReturnShippingAddress *ShippingDetailAddressPortable `json:"return_shipping_address,omitempty"`
+		RefundAmount          *Money                         `json:"refund_amount,omitempty"`
+	}
+
+	// MakeOfferRequest - https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes-actions_make-offer
+	MakeOfferRequest struct {
+		Note                  string                         `json:"note"`
+		OfferAmount           *Money                         `json:"offer_amount,omitempty"`
+		ReturnShippingAddress *ShippingDetailAddressPortable `json:"return_shipping_address,omitempty"`
+		InvoiceID             string                         `json:"invoice_id,omitempty"`
+		OfferType             DisputeOfferType               `json:"offer_type"`
+	}
+
+	// EvidenceRequest is the JSON part of ProvideEvidence and AppealDispute
+	EvidenceRequest struct {
+		Evidences []Evidence `json:"evidences"`
+	}
+
+	// Evidence - https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-evidence
+	Evidence struct {
+		EvidenceType EvidenceType  `json:"evidence_type"`
+		EvidenceInfo *EvidenceInfo `json:"evidence_info,omitempty"`
+		Notes        string        `json:"notes,omitempty"`
+	}
+
+	// EvidenceInfo struct
+	EvidenceInfo struct {
+		TrackingInfo []EvidenceTrackingInfo `json:"tracking_info,omitempty"`
+		RefundIDs    []string               `json:"refund_ids,omitempty"`
+	}
+
+	// EvidenceTrackingInfo struct
+	EvidenceTrackingInfo struct {
+		CarrierName      string `json:"carrier_name"`
+		CarrierNameOther string `json:"carrier_name_other,omitempty"`
+		TrackingURL      string `json:"tracking_url,omitempty"`
+		TrackingNumber   string `json:"tracking_number"`
+	}
+
+	// EvidenceFile is a document uploaded along with an EvidenceRequest.
+	// PayPal accepts JPG, GIF, PNG and PDF files
+	EvidenceFile struct {
+		Name        string
+		ContentType string
+		Content     io.Reader
+	}
+)
+
+// query returns the query string parameters of p, p may be nil
+func (p *ListDisputesParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if !p.StartTime.IsZero() {
+		q.Set("start_time", p.StartTime.UTC().Format(DateFormat))
+	}
+	if p.DisputedTransactionID != "" {
+		q.Set("disputed_transaction_id", p.DisputedTransactionID)
+	}
+	if p.DisputeState != "" {
+		q.Set("dispute_state", p.DisputeState)
+	}
+	if !p.UpdateTimeBefore.IsZero() {
+		q.Set("update_time_before", p.UpdateTimeBefore.UTC().Format(DateFormat))
+	}
+	if !p.UpdateTimeAfter.IsZero() {
+		q.Set("update_time_after", p.UpdateTimeAfter.UTC().Format(DateFormat))
+	}
+	if p.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(p.PageSize))
+	}
+	if p.NextPageToken != "" {
+		q.Set("next_page_token", p.NextPageToken)
+	}
+	return q
+}
+
+// ListDisputes lists disputes with a summary set of details
+// Endpoint: GET /v1/customer/disputes
+func (c *Client) ListDisputes(params *ListDisputesParams) (*ListDisputesResponse, error) {
+	return c.ListDisputesContext(context.Background(), params)
+}
+
+// ListDisputesContext is like ListDisputes but uses ctx for the request
+func (c *Client) ListDisputesContext(ctx context.Context, params *ListDisputesParams) (*ListDisputesResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/customer/disputes"), nil)
+	response := &ListDisputesResponse{}
+	if err != nil {
+		return response, err
+	}
+
+	req.URL.RawQuery = params.query().Encode()
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// GetDispute shows details for a dispute, by ID
+// Endpoint: GET /v1/customer/disputes/ID
+func (c *Client) GetDispute(disputeID string) (*Dispute, error) {
+	return c.GetDisputeContext(context.Background(), disputeID)
+}
+
+// GetDisputeContext is like GetDispute but uses ctx for the request
+func (c *Client) GetDisputeContext(ctx context.Context, disputeID string) (*Dispute, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/customer/disputes/"+disputeID), nil)
+	response := &Dispute{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// AcceptClaim accepts liability for a claim, which closes the dispute in the customer's favor
+// Endpoint: POST /v1/customer/disputes/ID/accept-claim
+func (c *Client) AcceptClaim(disputeID string, r AcceptClaimRequest) (*DisputeActionResponse, error) {
+	return c.AcceptClaimContext(context.Background(), disputeID, r)
+}
+
+// AcceptClaimContext is like AcceptClaim but uses ctx for the request
+func (c *Client) AcceptClaimContext(ctx context.Context, disputeID string, r AcceptClaimRequest) (*DisputeActionResponse, error) {
+	return c.disputeAction(ctx, disputeID, "accept-claim", r)
+}
+
+// ProvideEvidence provides evidence for a dispute, with optional documents.
+// The call is sent as multipart/form-data with the request in the `input` part
+// Endpoint: POST /v1/customer/disputes/ID/provide-evidence
+func (c *Client) ProvideEvidence(disputeID string, r EvidenceRequest, files ...EvidenceFile) (*DisputeActionResponse, error) {
+	return c.ProvideEvidenceContext(context.Background(), disputeID, r, files...)
+}
+
+// ProvideEvidenceContext is like ProvideEvidence but uses ctx for the request
+func (c *Client) ProvideEvidenceContext(ctx context.Context, disputeID string, r EvidenceRequest, files ...EvidenceFile) (*DisputeActionResponse, error) {
+	return c.disputeEvidence(ctx, disputeID, "provide-evidence", r, files)
+}
+
+// AppealDispute appeals a dispute in the customer's favor, with optional documents
+// Endpoint: POST /v1/customer/disputes/ID/appeal
+func (c *Client) AppealDispute(disputeID string, r EvidenceRequest, files ...EvidenceFile) (*DisputeActionResponse, error) {
+	return c.AppealDisputeContext(context.Background(), disputeID, r, files...)
+}
+
+// AppealDisputeContext is like AppealDispute but uses ctx for the request
+func (c *Client) AppealDisputeContext(ctx context.Context, disputeID string, r EvidenceRequest, files ...EvidenceFile) (*DisputeActionResponse, error) {
+	return c.disputeEvidence(ctx, disputeID, "appeal", r, files)
+}
+
+// SendDisputeMessage sends a message about a dispute to the other party
+// Endpoint: POST /v1/customer/disputes/ID/send-message
+func (c *Client) SendDisputeMessage(disputeID string, message string) (*DisputeActionResponse, error) {
+	return c.SendDisputeMessageContext(context.Background(), disputeID, message)
+}
+
+// SendDisputeMessageContext is like SendDisputeMessage but uses ctx for the request
+func (c *Client) SendDisputeMessageContext(ctx context.Context, disputeID string, message string) (*DisputeActionResponse, error) {
+	type sendMessageRequest struct {
+		Message string `json:"message"`
+	}
+
+	return c.disputeAction(ctx, disputeID, "send-message", sendMessageRequest{Message: message})
+}
+
+// MakeOffer makes an offer to the other party to resolve a dispute
+// Endpoint: POST /v1/customer/disputes/ID/make-offer
+func (c *Client) MakeOffer(disputeID string, r MakeOfferRequest) (*DisputeActionResponse, error) {
+	return c.MakeOfferContext(context.Background(), disputeID, r)
+}
+
+// MakeOfferContext is like MakeOffer but uses ctx for the request
+func (c *Client) MakeOfferContext(ctx context.Context, disputeID string, r MakeOfferRequest) (*DisputeActionResponse, error) {
+	return c.disputeAction(ctx, disputeID, "make-offer", r)
+}
+
+// EscalateDispute escalates the dispute, by ID, to a PayPal claim
+// Endpoint: POST /v1/customer/disputes/ID/escalate
+func (c *Client) EscalateDispute(disputeID string, note string) (*DisputeActionResponse, error) {
+	return c.EscalateDisputeContext(context.Background(), disputeID, note)
+}
+
+// EscalateDisputeContext is like EscalateDispute but uses ctx for the request
+func (c *Client) EscalateDisputeContext(ctx context.Context, disputeID string, note string) (*DisputeActionResponse, error) {
+	type escalateRequest struct {
+		Note string `json:"note"`
+	}
+
+	return c.disputeAction(ctx, disputeID, "escalate", escalateRequest{Note: note})
+}
+
+// disputeAction posts payload as JSON to the action endpoint of a dispute
+func (c *Client) disputeAction(ctx context.Context, disputeID, action string, payload interface{}) (*DisputeActionResponse, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/customer/disputes/"+disputeID+"/"+action), payload)
+	response := &DisputeActionResponse{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// disputeEvidence posts r and files as multipart/form-data to the action endpoint of a dispute
+func (c *Client) disputeEvidence(ctx context.Context, disputeID, action string, r EvidenceRequest, files []EvidenceFile) (*DisputeActionResponse, error) {
+	response := &DisputeActionResponse{}
+
+	buf := &bytes.Buffer{}
+	w := multipart.NewWriter(buf)
+
+	input, err := json.Marshal(r)
+	if err != nil {
+		return response, err
+	}
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="input"; filename="input.json"`)
+	h.Set("Content-Type", "application/json")
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return response, err
+	}
+	if _, err = part.Write(input); err != nil {
+		return response, err
+	}
+
+	for i, f := range files {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file%d"; filename=%q`, i+1, f.Name))
+		if f.ContentType != "" {
+			h.Set("Content-Type", f.ContentType)
+		} else {
+			h.Set("Content-Type", "application/octet-stream")
+		}
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return response, err
+		}
+		if _, err = io.Copy(part, f.Content); err != nil {
+			return response, err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return response, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/customer/disputes/"+disputeID+"/"+action), buf)
+	if err != nil {
+		return response, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
@@ -0,0 +1,89 @@
+package paypal
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListDisputes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/customer/disputes" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("dispute_state") != "REQUIRED_ACTION" || q.Get("page_size") != "5" || q.Get("start_time") != "" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"items":[{"dispute_id":"PP-D-4012","status":"WAITING_FOR_SELLER_RESPONSE"}],"links":[{"href":"next","rel":"next"}]}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	res, err := c.ListDisputes(&ListDisputesParams{DisputeState: "REQUIRED_ACTION", PageSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 1 || res.Items[0].DisputeID != "PP-D-4012" || res.Items[0].Status != DisputeStatusWaitingForSellerResponse {
+		t.Fatalf("unexpected response %+v", res)
+	}
+}
+
+func TestProvideEvidence(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/customer/disputes/PP-D-4012/provide-evidence" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("unexpected body: %v", err)
+			return
+		}
+		if len(r.MultipartForm.File["input"]) != 1 || len(r.MultipartForm.File["file1"]) != 1 {
+			t.Errorf("unexpected parts %v", r.MultipartForm.File)
+			return
+		}
+
+		input := EvidenceRequest{}
+		in, err := r.MultipartForm.File["input"][0].Open()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if err = json.NewDecoder(in).Decode(&input); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(input.Evidences) != 1 || input.Evidences[0].EvidenceType != EvidenceTypeProofOfFulfillment {
+			t.Errorf("unexpected input %+v", input)
+		}
+
+		f, err := r.MultipartForm.File["file1"][0].Open()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		content, _ := ioutil.ReadAll(f)
+		if string(content) != "%PDF" {
+			t.Errorf("unexpected file content %q", content)
+		}
+		w.Write([]byte(`{"links":[{"href":"dispute","rel":"self"}]}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	res, err := c.ProvideEvidence("PP-D-4012", EvidenceRequest{
+		Evidences: []Evidence{{EvidenceType: EvidenceTypeProofOfFulfillment, Notes: "shipped"}},
+	}, EvidenceFile{Name: "receipt.pdf", ContentType: "application/pdf", Content: strings.NewReader("%PDF")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Links) != 1 {
+		t.Fatalf("unexpected response %+v", res)
+	}
+}
@@ -0,0 +1,171 @@
+package paypal
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Issue is an error code of PayPal: the name of an ErrorResponse or the issue of one of
+// its details. Issues match errors with errors.Is:
+//
+//	if errors.Is(err, paypal.ErrInstrumentDeclined) {
+//		// ask the payer for another funding source
+//	}
+//
+// Issue(code) matches any other code, e.g. paypal.Issue(paypal.IssueAmountMismatch)
+type Issue string
+
+// Error returns the code of i
+func (i Issue) Error() string {
+	return "paypal: " + string(i)
+}
+
+// Error names, PayPal answers them with the status noted
+var (
+	// ErrInvalidRequest - 400, the request is malformed or violates the schema
+	ErrInvalidRequest = Issue("INVALID_REQUEST")
+	// ErrAuthenticationFailure - 401, the credentials or the access token are invalid
+	ErrAuthenticationFailure = Issue("AUTHENTICATION_FAILURE")
+	// ErrNotAuthorized - 403, the client is not allowed to perform the call
+	ErrNotAuthorized = Issue("NOT_AUTHORIZED")
+	// ErrResourceNotFound - 404
+	ErrResourceNotFound = Issue("RESOURCE_NOT_FOUND")
+	// ErrUnprocessableEntity - 422, the call failed business validation, see the issues of its details
+	ErrUnprocessableEntity = Issue("UNPROCESSABLE_ENTITY")
+	// ErrRateLimitReached - 429
+	ErrRateLimitReached = Issue("RATE_LIMIT_REACHED")
+	// ErrInternalServerError - 500
+	ErrInternalServerError = Issue("INTERNAL_SERVER_ERROR")
+	// ErrServiceUnavailable - 503
+	ErrServiceUnavailable = Issue("SERVICE_UNAVAILABLE")
+)
+
+// Issues of orders and payments, found in the details of an ErrorResponse
+var (
+	// ErrInstrumentDeclined - the funding source was declined, redirect the payer to choose another, see PayerActionLink
+	ErrInstrumentDeclined = Issue("INSTRUMENT_DECLINED")
+	// ErrPayerActionRequired - the payer must complete an action, e.g. 3D Secure, see PayerActionLink
+	ErrPayerActionRequired = Issue("PAYER_ACTION_REQUIRED")
+	// ErrPayerCannotPay - the payer cannot pay with PayPal for this transaction
+	ErrPayerCannotPay = Issue("PAYER_CANNOT_PAY")
+	// ErrTransactionRefused - the transaction was refused
+	ErrTransactionRefused = Issue("TRANSACTION_REFUSED")
+	// ErrOrderNotApproved - the payer has not approved the order yet
+	ErrOrderNotApproved = Issue("ORDER_NOT_APPROVED")
+	// ErrOrderAlreadyCaptured - an order with intent CAPTURE is captured once
+	ErrOrderAlreadyCaptured = Issue("ORDER_ALREADY_CAPTURED")
+	// ErrOrderAlreadyAuthorized - an order with intent AUTHORIZE is authorized once
+	ErrOrderAlreadyAuthorized = Issue("ORDER_ALREADY_AUTHORIZED")
+	// ErrOrderAlreadyCompleted - a completed order cannot be updated
+	ErrOrderAlreadyCompleted = Issue("ORDER_ALREADY_COMPLETED")
+	// ErrOrderExpired - the order expired before it was captured
+	ErrOrderExpired = Issue("ORDER_EXPIRED")
+	// ErrActionDoesNotMatchIntent - the order was created with the other intent
+	ErrActionDoesNotMatchIntent = Issue("ACTION_DOES_NOT_MATCH_INTENT")
+	// ErrDuplicateInvoiceID - the invoice ID was already used for a payment
+	ErrDuplicateInvoiceID = Issue("DUPLICATE_INVOICE_ID")
+	// ErrAuthorizationExpired - the authorization can no longer be captured
+	ErrAuthorizationExpired = Issue("AUTHORIZATION_EXPIRED")
+	// ErrAuthorizationVoided - the authorization was voided
+	ErrAuthorizationVoided = Issue("AUTHORIZATION_VOIDED")
+	// ErrAuthorizationAlreadyCaptured - the authorization was fully captured
+	ErrAuthorizationAlreadyCaptured = Issue("AUTHORIZATION_ALREADY_CAPTURED")
+	// ErrCaptureFullyRefunded - the capture was already refunded in full
+	ErrCaptureFullyRefunded = Issue("CAPTURE_FULLY_REFUNDED")
+	// ErrRefundAmountExceeded - the refund is larger than what is left of the capture
+	ErrRefundAmountExceeded = Issue("REFUND_AMOUNT_EXCEEDED")
+	// ErrPermissionDenied - the partner has no permission to act for the merchant
+	ErrPermissionDenied = Issue("PERMISSION_DENIED")
+)
+
+// statusIssues are the names PayPal gives to errors by status, matched even
+// when the body has no name, e.g. 401 invalid_token or a 503 from a proxy
+var statusIssues = map[int]Issue{
+	http.StatusBadRequest:          ErrInvalidRequest,
+	http.StatusUnauthorized:        ErrAuthenticationFailure,
+	http.StatusForbidden:           ErrNotAuthorized,
+	http.StatusNotFound:            ErrResourceNotFound,
+	http.StatusUnprocessableEntity: ErrUnprocessableEntity,
+	http.StatusTooManyRequests:     ErrRateLimitReached,
+	http.StatusInternalServerError: ErrInternalServerError,
+	http.StatusServiceUnavailable:  ErrServiceUnavailable,
+}
+
+// Is reports whether target is an Issue of r: its name, the name of its status
+// or the issue of one of its details
+func (r *ErrorResponse) Is(target error) bool {
+	issue, ok := target.(Issue)
+	if !ok {
+		return false
+	}
+	if r.Name == string(issue) {
+		return true
+	}
+	if r.Response != nil && statusIssues[r.Response.StatusCode] == issue {
+		return true
+	}
+	for _, d := range r.Details {
+		if d.Issue == string(issue) {
+			return true
+		}
+	}
+	return false
+}
+
+// Retryable reports whether r is transient: the same call may succeed later.
+// Only retry calls which are idempotent or carry a PayPal-Request-Id
+func (r *ErrorResponse) Retryable() bool {
+	if r.Response != nil && retryableStatus(r.Response.StatusCode) {
+		return true
+	}
+	return r.Name == string(ErrRateLimitReached) || r.Name == string(ErrInternalServerError) || r.Name == string(ErrServiceUnavailable)
+}
+
+// PayerActionLink returns the URL to redirect the payer to when PayPal needs
+// them to act, e.g. for PAYER_ACTION_REQUIRED or INSTRUMENT_DECLINED.
+// The links of the response come first, then those of its details
+func (r *ErrorResponse) PayerActionLink() (string, bool) {
+	links := r.Links
+	for _, d := range r.Details {
+		links = append(links[:len(links):len(links)], d.Links...)
+	}
+	for _, rel := range []string{"payer-action", "redirect", "approve"} {
+		for _, l := range links {
+			if l.Rel == rel && l.Href != "" {
+				return l.Href, true
+			}
+		}
+	}
+	return "", false
+}
+
+// UnmarshalJSON decodes d, reading Links from "link" too, as some APIs name them
+func (d *ErrorResponseDetail) UnmarshalJSON(b []byte) error {
+	type detail ErrorResponseDetail
+	var v struct {
+		detail
+		Link []Link `json:"link"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*d = ErrorResponseDetail(v.detail)
+	if len(d.Links) == 0 {
+		d.Links = v.Link
+	}
+	return nil
+}
+
+// Is reports whether target is the Issue of one of the details of e
+func (e *ValidationError) Is(target error) bool {
+	issue, ok := target.(Issue)
+	if !ok {
+		return false
+	}
+	for _, d := range e.Details {
+		if d.Issue == string(issue) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package paypal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorClassification(t *testing.T) {
+	var status int
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	status, body = http.StatusUnprocessableEntity, `{"name":"UNPROCESSABLE_ENTITY","details":[{"issue":"INSTRUMENT_DECLINED"}],
+		"links":[{"href":"https://www.paypal.com/checkoutnow?token=5O190127TN364715T","rel":"redirect","method":"GET"}]}`
+	_, err := c.CaptureOrder("5O190127TN364715T", CaptureOrderRequest{})
+	if !errors.Is(err, ErrInstrumentDeclined) || !errors.Is(err, ErrUnprocessableEntity) || errors.Is(err, ErrOrderAlreadyCaptured) {
+		t.Fatalf("unexpected classification of %v", err)
+	}
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Retryable() {
+		t.Fatalf("expected a permanent *ErrorResponse, got %v", err)
+	}
+	if link, ok := errResp.PayerActionLink(); !ok || link != "https://www.paypal.com/checkoutnow?token=5O190127TN364715T" {
+		t.Fatalf("unexpected payer action link %q", link)
+	}
+
+	status, body = http.StatusUnprocessableEntity, `{"name":"UNPROCESSABLE_ENTITY","details":[{"issue":"PAYER_ACTION_REQUIRED",
+		"links":[{"href":"https://www.paypal.com/checkoutnow?token=5O190127TN364715T","rel":"payer-action","method":"GET"}]}]}`
+	_, err = c.CaptureOrder("5O190127TN364715T", CaptureOrderRequest{})
+	if !errors.Is(err, ErrPayerActionRequired) || !errors.As(err, &errResp) {
+		t.Fatalf("expected PAYER_ACTION_REQUIRED, got %v", err)
+	}
+	if link, ok := errResp.PayerActionLink(); !ok || link != "https://www.paypal.com/checkoutnow?token=5O190127TN364715T" {
+		t.Fatalf("unexpected payer action link %q in details", link)
+	}
+
+	status, body = http.StatusNotFound, ``
+	if _, err = c.GetOrder("O-1"); !errors.Is(err, ErrResourceNotFound) {
+		t.Fatalf("expected a 404 to be RESOURCE_NOT_FOUND, got %v", err)
+	}
+
+	status, body = http.StatusTooManyRequests, `{"name":"RATE_LIMIT_REACHED"}`
+	_, err = c.GetOrder("O-1")
+	if !errors.Is(err, ErrRateLimitReached) || !errors.As(err, &errResp) || !errResp.Retryable() {
+		t.Fatalf("expected a retryable rate limit, got %v", err)
+	}
+	if _, ok := errResp.PayerActionLink(); ok {
+		t.Fatalf("expected no payer action link")
+	}
+
+	verr := ValidatePurchaseUnits([]PurchaseUnitRequest{{Amount: &PurchaseUnitAmount{Currency: "USD", Value: "1.001"}}})
+	if !errors.Is(verr, Issue(IssueDecimalPrecision)) || errors.Is(verr, Issue(IssueAmountMismatch)) {
+		t.Fatalf("unexpected classification of %v", verr)
+	}
+}
@@ -1 +1,3 @@
 module github.com/siriele/paypal
+
+go 1.18
@@ -0,0 +1,52 @@
+package paypal
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// RequestOption customizes the HTTP request of a single API call
+type RequestOption func(*http.Request)
+
+// WithRequestID sets the PayPal-Request-Id idempotency key of the call.
+// Replaying a call with the same key returns the original result instead of
+// moving money twice, so the call can be safely retried after a timeout
+func WithRequestID(requestID string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(HeaderRequestID, requestID)
+	}
+}
+
+// SetRequestIDGenerator sets a generator of idempotency keys for the calls
+// which move money or create resources: CreateOrder, AuthorizeOrder, CaptureOrder,
+// ConfirmPaymentSource, AddTracking, CaptureAuthorization, RefundCapture,
+// CreateSinglePayout, CreateProduct, CreateSubscriptionPlan, CreateSubscription,
+// CaptureSubscription and BillBillingAgreementBalance.
+// A key given with WithRequestID takes precedence. Passing nil disables generation
+func (c *Client) SetRequestIDGenerator(generator func() string) {
+	c.requestIDGenerator = generator
+}
+
+// NewRequestID returns a random UUID (version 4), suitable as a PayPal-Request-Id
+func NewRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("paypal: unable to generate request ID: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// applyIdempotency applies opts to req and generates a PayPal-Request-Id
+// when none was given and c has a generator
+func (c *Client) applyIdempotency(req *http.Request, opts []RequestOption) {
+	for _, opt := range opts {
+		opt(req)
+	}
+	if req.Header.Get(HeaderRequestID) == "" && c.requestIDGenerator != nil {
+		req.Header.Set(HeaderRequestID, c.requestIDGenerator())
+	}
+}
@@ -0,0 +1,267 @@
+package paypal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// LinkRelNext is the rel of the link to the next page of a list
+const LinkRelNext string = "next"
+
+type (
+	// PageFunc fetches the page of a list designated by cursor, which is
+	// empty for the first page. It returns the items of the page and the
+	// cursor of the next one, or an empty cursor on the last page.
+	//
+	// The cursor is opaque to the Iterator: list endpoints use the href of
+	// the next link or a page number
+	PageFunc[T any] func(ctx context.Context, cursor string) (items []T, next string, err error)
+
+	// Iterator walks the items of a list endpoint across pages.
+	//
+	//	it := c.ListDisputesIterator(ctx, nil)
+	//	for it.Next() {
+	//		dispute := it.Item()
+	//	}
+	//	if err := it.Err(); err != nil {
+	//	}
+	//
+	// Pages are fetched lazily, iteration stops when ctx is done
+	Iterator[T any] struct {
+		ctx   context.Context
+		fetch PageFunc[T]
+
+		items  []T
+		item   T
+		cursor string
+		last   bool
+		err    error
+	}
+)
+
+// NewIterator returns an Iterator over the pages returned by fetch.
+// Use it to iterate list endpoints which have no built-in iterator
+func NewIterator[T any](ctx context.Context, fetch PageFunc[T]) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch}
+}
+
+// Next advances to the next item, fetching the next page when needed.
+// It returns false at the end of the list or on error, see Err
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.err = it.ctx.Err(); it.err != nil {
+		return false
+	}
+
+	for len(it.items) == 0 {
+		if it.last {
+			return false
+		}
+		cursor := it.cursor
+		it.items, it.cursor, it.err = it.fetch(it.ctx, cursor)
+		if it.err != nil {
+			return false
+		}
+		// A page repeating its own cursor would loop forever
+		if it.cursor == "" || it.cursor == cursor {
+			it.last = true
+		}
+	}
+
+	it.item, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Item returns the current item, call Next first
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// NextLink returns the href of the next link, or an empty string
+func NextLink(links []Link) string {
+	for _, l := range links {
+		if l.Rel == LinkRelNext {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// resolveLink returns href relative to c.APIBase, so a link from a response
+// never sends the token to another host
+func (c *Client) resolveLink(href string) (string, error) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", err
+	}
+	u.Scheme, u.Host, u.User = "", "", nil
+
+	return c.APIBase + u.String(), nil
+}
+
+// linkPages returns a PageFunc starting at first and following next links.
+// page decodes a response into its items and links
+func linkPages[T any, R any](c *Client, first string, page func(*R) ([]T, []Link)) PageFunc[T] {
+	return func(ctx context.Context, cursor string) ([]T, string, error) {
+		u := first
+		if cursor != "" {
+			var err error
+			if u, err = c.resolveLink(cursor); err != nil {
+				return nil, "", err
+			}
+		}
+
+		req, err := c.NewRequestContext(ctx, "GET", u, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		response := new(R)
+		if err = c.SendWithAuth(req, response); err != nil {
+			return nil, "", err
+		}
+
+		items, links := page(response)
+		return items, NextLink(links), nil
+	}
+}
+
+// ListBillingPlansIterator iterates billing plans, page after page
+// Endpoint: GET /v1/payments/billing-plans
+func (c *Client) ListBillingPlansIterator(ctx context.Context, bplp BillingPlanListParams) *Iterator[BillingPlan] {
+	// Pages are numbered from 0
+	page, _ := strconv.Atoi(bplp.Page)
+
+	return NewIterator(ctx, func(ctx context.Context, cursor string) ([]BillingPlan, string, error) {
+		if cursor != "" {
+			var err error
+			if page, err = strconv.Atoi(cursor); err != nil {
+				return nil, "", fmt.Errorf("paypal: invalid billing plans page %q", cursor)
+			}
+		}
+		bplp.Page = strconv.Itoa(page)
+		response, err := c.ListBillingPlansContext(ctx, bplp)
+		if err != nil {
+			return nil, "", err
+		}
+
+		next := ""
+		if total, err := strconv.Atoi(response.TotalPages); NextLink(response.Links) != "" || (err == nil && page+1 < total) {
+			next = strconv.Itoa(page + 1)
+		}
+
+		return response.Plans, next, nil
+	})
+}
+
+// GetCreditCardsIterator iterates the credit cards stored in the vault, page after page
+// Endpoint: GET /v1/vault/credit-cards
+func (c *Client) GetCreditCardsIterator(ctx context.Context, ccf *CreditCardsFilter) *Iterator[CreditCard] {
+	filter := CreditCardsFilter{Page: 1}
+	if ccf != nil {
+		filter = *ccf
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	return NewIterator(ctx, func(ctx context.Context, cursor string) ([]CreditCard, string, error) {
+		if cursor != "" {
+			page, err := strconv.Atoi(cursor)
+			if err != nil {
+				return nil, "", fmt.Errorf("paypal: invalid credit cards page %q", cursor)
+			}
+			filter.Page = page
+		}
+		response, err := c.GetCreditCardsContext(ctx, &filter)
+		if err != nil {
+			return nil, "", err
+		}
+
+		next := ""
+		if filter.Page < response.TotalPages || (response.TotalPages == 0 && NextLink(response.Links) != "") {
+			next = strconv.Itoa(filter.Page + 1)
+		}
+
+		return response.Items, next, nil
+	})
+}
+
+// GetWebProfilesIterator iterates web experience profiles.
+// PayPal returns all of them at once, so there is a single page
+// Endpoint: GET /v1/payment-experience/web-profiles
+func (c *Client) GetWebProfilesIterator(ctx context.Context) *Iterator[WebProfile] {
+	return NewIterator(ctx, func(ctx context.Context, cursor string) ([]WebProfile, string, error) {
+		wps, err := c.GetWebProfilesContext(ctx)
+		return wps, "", err
+	})
+}
+
+// ListDisputesIterator iterates disputes, following next links
+// Endpoint: GET /v1/customer/disputes
+func (c *Client) ListDisputesIterator(ctx context.Context, params *ListDisputesParams) *Iterator[Dispute] {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/customer/disputes"), nil)
+	if err != nil {
+		return failedIterator[Dispute](ctx, err)
+	}
+	req.URL.RawQuery = params.query().Encode()
+
+	return NewIterator(ctx, linkPages(c, req.URL.String(), func(r *ListDisputesResponse) ([]Dispute, []Link) {
+		return r.Items, r.Links
+	}))
+}
+
+// ListWebhookEventsIterator iterates event notifications, following next links
+// Endpoint: GET /v1/notifications/webhooks-events
+func (c *Client) ListWebhookEventsIterator(ctx context.Context, params *ListWebhookEventsParams) *Iterator[WebhookEvent] {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks-events"), nil)
+	if err != nil {
+		return failedIterator[WebhookEvent](ctx, err)
+	}
+	req.URL.RawQuery = params.query().Encode()
+
+	return NewIterator(ctx, linkPages(c, req.URL.String(), func(r *ListWebhookEventsResponse) ([]WebhookEvent, []Link) {
+		return r.Events, r.Links
+	}))
+}
+
+// ListProductsIterator iterates the products of the catalog, following next links
+// Endpoint: GET /v1/catalogs/products
+func (c *Client) ListProductsIterator(ctx context.Context, params *ListProductsParams) *Iterator[Product] {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/catalogs/products"), nil)
+	if err != nil {
+		return failedIterator[Product](ctx, err)
+	}
+	req.URL.RawQuery = params.query().Encode()
+
+	return NewIterator(ctx, linkPages(c, req.URL.String(), func(r *ListProductsResponse) ([]Product, []Link) {
+		return r.Products, r.Links
+	}))
+}
+
+// ListSubscriptionPlansIterator iterates subscription plans, following next links
+// Endpoint: GET /v1/billing/plans
+func (c *Client) ListSubscriptionPlansIterator(ctx context.Context, params *ListSubscriptionPlansParams) *Iterator[SubscriptionPlan] {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/plans"), nil)
+	if err != nil {
+		return failedIterator[SubscriptionPlan](ctx, err)
+	}
+	req.URL.RawQuery = params.query().Encode()
+
+	return NewIterator(ctx, linkPages(c, req.URL.String(), func(r *ListSubscriptionPlansResponse) ([]SubscriptionPlan, []Link) {
+		return r.Plans, r.Links
+	}))
+}
+
+// failedIterator returns an Iterator which stops with err
+func failedIterator[T any](ctx context.Context, err error) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, err: err}
+}
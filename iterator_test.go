@@ -0,0 +1,95 @@
+package paypal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListDisputesIterator(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/customer/disputes" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("expected the token to be sent on every page")
+			return
+		}
+		switch r.URL.Query().Get("next_page_token") {
+		case "":
+			if r.URL.Query().Get("dispute_state") != "REQUIRED_ACTION" {
+				t.Errorf("expected params on the first page, got %s", r.URL.RawQuery)
+				return
+			}
+			// Links are absolute and point to the real API
+			fmt.Fprint(w, `{"items":[{"dispute_id":"PP-D-1"},{"dispute_id":"PP-D-2"}],"links":[
+				{"rel":"next","href":"https://api.paypal.com/v1/customer/disputes?dispute_state=REQUIRED_ACTION&next_page_token=p2"}]}`)
+		case "p2":
+			fmt.Fprint(w, `{"items":[{"dispute_id":"PP-D-3"}],"links":[{"rel":"self","href":"https://api.paypal.com/v1/customer/disputes"}]}`)
+		default:
+			t.Errorf("unexpected page %s", r.URL.RawQuery)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	var ids []string
+	it := c.ListDisputesIterator(context.Background(), &ListDisputesParams{DisputeState: "REQUIRED_ACTION"})
+	for it.Next() {
+		ids = append(ids, it.Item().DisputeID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[PP-D-1 PP-D-2 PP-D-3]" {
+		t.Fatalf("unexpected disputes %v", ids)
+	}
+}
+
+func TestGetCreditCardsIterator(t *testing.T) {
+	pages := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		page := r.URL.Query().Get("page")
+		fmt.Fprintf(w, `{"items":[{"id":"CARD-%s"}],"total_items":2,"total_pages":2}`, page)
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	var ids []string
+	it := c.GetCreditCardsIterator(context.Background(), &CreditCardsFilter{PageSize: 1})
+	for it.Next() {
+		ids = append(ids, it.Item().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[CARD-1 CARD-2]" || pages != 2 {
+		t.Fatalf("unexpected cards %v fetched in %d pages", ids, pages)
+	}
+}
+
+func TestIterator_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewIterator(ctx, func(ctx context.Context, cursor string) ([]int, string, error) {
+		return []int{1, 2}, "more", nil
+	})
+
+	if !it.Next() || it.Item() != 1 {
+		t.Fatalf("expected the first item")
+	}
+	cancel()
+	if it.Next() {
+		t.Fatalf("expected iteration to stop once ctx is cancelled")
+	}
+	if it.Err() != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", it.Err())
+	}
+}
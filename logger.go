@@ -0,0 +1,177 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"time"
+)
+
+// HeaderDebugID identifies a call when reporting it to PayPal
+const HeaderDebugID = "Paypal-Debug-Id"
+
+// Levels of the messages logged by a Client, their values match those of log/slog
+const (
+	LevelDebug LogLevel = -4
+	LevelInfo  LogLevel = 0
+	LevelWarn  LogLevel = 4
+	LevelError LogLevel = 8
+)
+
+// redacted replaces the secrets removed from logs
+const redacted = "[REDACTED]"
+
+type (
+	// LogLevel is the severity of a logged message
+	LogLevel int
+
+	// LogField is a key-value pair attached to a logged message
+	LogField struct {
+		Key   string
+		Value interface{}
+	}
+
+	// Logger receives a message for every HTTP attempt made by a Client. Messages
+	// carry the fields method, path, attempt, latency and either status and
+	// debug_id or error. At LevelDebug they also carry request_body and
+	// response_body.
+	//
+	// Secrets are redacted before they reach the Logger: Authorization headers,
+	// card numbers, security codes and tokens
+	Logger interface {
+		// Enabled reports whether messages of level are logged, bodies are only read for LevelDebug
+		Enabled(ctx context.Context, level LogLevel) bool
+		Log(ctx context.Context, level LogLevel, msg string, fields ...LogField)
+	}
+)
+
+var (
+	// sensitiveHeaders are the headers whose values are redacted from logs
+	sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+	// jsonSecrets matches the secrets of JSON bodies, card numbers included
+	jsonSecrets = regexp.MustCompile(`("(?:number|cvv2|security_code|access_token|refresh_token|id_token|client_secret|password)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+	// formSecrets matches the secrets of form bodies, e.g. of identity token requests
+	formSecrets = regexp.MustCompile(`(?m)((?:^|&)(?:code|refresh_token|access_token|client_secret|password)=)[^&\s]*`)
+)
+
+// String returns the name of l
+func (l LogLevel) String() string {
+	switch {
+	case l < LevelInfo:
+		return "DEBUG"
+	case l < LevelWarn:
+		return "INFO"
+	case l < LevelError:
+		return "WARN"
+	}
+	return "ERROR"
+}
+
+// SetLogger sets the structured logger of the HTTP attempts made by c,
+// passing nil disables it. It is independent of SetLog
+func (c *Client) SetLogger(l Logger) {
+	c.logger = l
+}
+
+// log reports an attempt to the Logger and dumps it to the Log of c
+func (c *Client) log(r *http.Request, resp *http.Response, err error, attempt int, latency time.Duration) {
+	if c.logger != nil {
+		c.logAttempt(r, resp, err, attempt, latency)
+	}
+
+	if c.Log != nil {
+		if r != nil {
+			reqDump, _ := httputil.DumpRequestOut(redactRequest(r), true)
+			c.Log.Write([]byte(fmt.Sprintf("\nRequest: %s\n", redactBody(reqDump))))
+		}
+		if resp != nil {
+			redactedResp := *resp
+			redactedResp.Header = redactHeader(resp.Header)
+			respDump, _ := httputil.DumpResponse(&redactedResp, true)
+			// DumpResponse replaced the body it read
+			resp.Body = redactedResp.Body
+			c.Log.Write([]byte(fmt.Sprintf("\nResponse: %s\n", redactBody(respDump))))
+		}
+	}
+}
+
+// logAttempt reports an attempt to c.logger, at a level depending on its outcome
+func (c *Client) logAttempt(r *http.Request, resp *http.Response, err error, attempt int, latency time.Duration) {
+	ctx := r.Context()
+	level, msg := LevelInfo, "paypal: request"
+	switch {
+	case err != nil || resp.StatusCode >= 500:
+		level, msg = LevelError, "paypal: request failed"
+	case resp.StatusCode >= 400:
+		level, msg = LevelWarn, "paypal: request failed"
+	}
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	fields := []LogField{
+		{"method", r.Method},
+		{"path", r.URL.Path},
+		{"attempt", attempt},
+		{"latency", latency},
+	}
+	if err != nil {
+		fields = append(fields, LogField{"error", err.Error()})
+	} else {
+		fields = append(fields, LogField{"status", resp.StatusCode}, LogField{"debug_id", resp.Header.Get(HeaderDebugID)})
+	}
+
+	if c.logger.Enabled(ctx, LevelDebug) {
+		if r.GetBody != nil {
+			if body, berr := r.GetBody(); berr == nil {
+				b, _ := ioutil.ReadAll(body)
+				fields = append(fields, LogField{"request_body", string(redactBody(b))})
+			}
+		}
+		if resp != nil {
+			// The body is read for the log and put back for Send
+			b, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+			fields = append(fields, LogField{"response_body", string(redactBody(b))})
+		}
+	}
+
+	c.logger.Log(ctx, level, msg, fields...)
+}
+
+// redactRequest returns a copy of r without the values of its sensitive headers,
+// with a fresh body when r can rewind it
+func redactRequest(r *http.Request) *http.Request {
+	redactedReq := r.Clone(r.Context())
+	redactedReq.Header = redactHeader(r.Header)
+	if r.GetBody != nil {
+		if body, err := r.GetBody(); err == nil {
+			redactedReq.Body = body
+		}
+	}
+	return redactedReq
+}
+
+// redactHeader returns a copy of h without the values of its sensitive headers
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, key := range sensitiveHeaders {
+		if h.Get(key) != "" {
+			h.Set(key, redacted)
+		}
+	}
+	return h
+}
+
+// redactBody replaces the secrets of a JSON or form body
+func redactBody(b []byte) []byte {
+	b = jsonSecrets.ReplaceAll(b, []byte(`$1"`+redacted+`"`))
+	return formSecrets.ReplaceAll(b, []byte(`${1}`+redacted))
+}
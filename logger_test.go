@@ -0,0 +1,87 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// recordLogger records the messages of level and above
+type recordLogger struct {
+	level   LogLevel
+	records []map[string]interface{}
+	levels  []LogLevel
+}
+
+func (l *recordLogger) Enabled(ctx context.Context, level LogLevel) bool {
+	return level >= l.level
+}
+
+func (l *recordLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	record := map[string]interface{}{}
+	for _, f := range fields {
+		record[f.Key] = f.Value
+	}
+	l.records = append(l.records, record)
+	l.levels = append(l.levels, level)
+}
+
+func TestLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oauth2/token" {
+			w.Write([]byte(`{"access_token":"A21AAFEpH4PsADK7qSS7pSRsgzfENtu","expires_in":32400}`))
+			return
+		}
+		w.Header().Set(HeaderDebugID, "662121ee369c0")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"name":"RESOURCE_NOT_FOUND"}`))
+	}))
+	defer ts.Close()
+
+	var dump bytes.Buffer
+	l := &recordLogger{level: LevelInfo}
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetLog(&dump)
+	c.SetLogger(l)
+
+	if _, err := c.GetOrder("O-1"); err == nil {
+		t.Fatal("expected a 404")
+	}
+
+	if len(l.records) != 2 || l.levels[0] != LevelInfo || l.levels[1] != LevelWarn {
+		t.Fatalf("expected a token request and a failed request, got %v at %v", l.records, l.levels)
+	}
+	failed := l.records[1]
+	if failed["method"] != "GET" || failed["path"] != "/v2/checkout/orders/O-1" || failed["status"] != http.StatusNotFound ||
+		failed["debug_id"] != "662121ee369c0" || failed["attempt"] != 1 {
+		t.Fatalf("unexpected record %v", failed)
+	}
+	if _, ok := failed["response_body"]; ok {
+		t.Fatalf("expected no body below LevelDebug")
+	}
+
+	out := dump.String()
+	if strings.Count(out, "\nRequest: ") != 2 || strings.Count(out, "\nResponse: ") != 2 {
+		t.Fatalf("expected labelled requests and responses, got %s", out)
+	}
+	if strings.Contains(out, "A21AAFEpH4PsADK7qSS7pSRsgzfENtu") || strings.Contains(out, "Basic ") || strings.Contains(out, "Bearer ") {
+		t.Fatalf("expected secrets to be redacted, got %s", out)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct{ body, expected string }{
+		{`{"number": "4417119669820331", "cvv2":"874","type":"visa"}`, `{"number": "[REDACTED]", "cvv2":"[REDACTED]","type":"visa"}`},
+		{`{"payment_source":{"card":{"security_code":"123","name":"J \"D\""}}}`, `{"payment_source":{"card":{"security_code":"[REDACTED]","name":"J \"D\""}}}`},
+		{`grant_type=authorization_code&code=C21AA`, `grant_type=authorization_code&code=[REDACTED]`},
+		{`refresh_token=R23A&grant_type=refresh_token`, `refresh_token=[REDACTED]&grant_type=refresh_token`},
+	}
+	for _, tt := range tests {
+		if got := string(redactBody([]byte(tt.body))); got != tt.expected {
+			t.Errorf("redactBody(%s): expected %s, got %s", tt.body, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package paypal
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// HeaderAuthAssertion identifies the merchant a partner acts on behalf of
+	HeaderAuthAssertion = "PayPal-Auth-Assertion"
+	// HeaderPartnerAttributionID carries the BN code which attributes calls to a partner
+	HeaderPartnerAttributionID = "PayPal-Partner-Attribution-Id"
+)
+
+// clientToken shares the token of a Client with the clients derived from it
+type clientToken struct {
+	c *Client
+}
+
+// AuthAssertion returns the PayPal-Auth-Assertion by which the partner clientID
+// acts on behalf of the merchant payerID. It is an unsigned JWT
+func AuthAssertion(clientID, payerID string) string {
+	header, _ := json.Marshal(map[string]string{"alg": "none"})
+	payload, _ := json.Marshal(map[string]string{"iss": clientID, "payer_id": payerID})
+
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+}
+
+// WithAuthAssertion makes the call on behalf of the merchant payerID,
+// clientID is the client ID of the partner
+func WithAuthAssertion(clientID, payerID string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(HeaderAuthAssertion, AuthAssertion(clientID, payerID))
+	}
+}
+
+// WithPartnerAttributionID attributes the call to the partner with bnCode
+func WithPartnerAttributionID(bnCode string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(HeaderPartnerAttributionID, bnCode)
+	}
+}
+
+// SetPartnerAttributionID sets the BN code sent as PayPal-Partner-Attribution-Id
+// with every request authorized by SendWithAuth. Passing "" removes it
+func (c *Client) SetPartnerAttributionID(bnCode string) {
+	c.setHeader(HeaderPartnerAttributionID, bnCode)
+}
+
+// ForMerchant returns a client acting on behalf of the merchant payerID, onboarded
+// by the partner owning the credentials of c: every request it authorizes carries
+// a PayPal-Auth-Assertion.
+//
+// The returned client shares the access token and the rate limits of c, and takes
+// its HTTP client, logs, retry policy, request ID generator, middlewares and partner
+// attribution ID as they are when ForMerchant is called
+func (c *Client) ForMerchant(payerID string) *Client {
+	c.Lock()
+	defer c.Unlock()
+
+	m := &Client{
+		Client:             c.Client,
+		ClientID:           c.ClientID,
+		Secret:             c.Secret,
+		APIBase:            c.APIBase,
+		Log:                c.Log,
+		logger:             c.logger,
+		tokenSource:        clientToken{c: c},
+		retryPolicy:        c.retryPolicy,
+		requestIDGenerator: c.requestIDGenerator,
+		headers:            c.headers.Clone(),
+	}
+	if m.headers == nil {
+		m.headers = http.Header{}
+	}
+	m.headers.Set(HeaderAuthAssertion, AuthAssertion(c.ClientID, payerID))
+	// PayPal limits the partner, whichever merchant it acts for
+	m.limitsOnce.Do(func() { m.limits = c.rateLimits() })
+
+	c.middlewareMu.Lock()
+	m.middlewares = c.middlewares
+	c.middlewareMu.Unlock()
+
+	return m
+}
+
+// setHeader sets a header sent with every authorized request, "" removes it
+func (c *Client) setHeader(key, value string) {
+	c.Lock()
+	defer c.Unlock()
+
+	// Requests in flight may be reading the current headers
+	headers := c.headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	if value == "" {
+		headers.Del(key)
+	} else {
+		headers.Set(key, value)
+	}
+	c.headers = headers
+}
+
+// applyHeaders adds the headers of c to req, unless the call set them
+func (c *Client) applyHeaders(req *http.Request) {
+	c.Lock()
+	headers := c.headers
+	c.Unlock()
+
+	for key, values := range headers {
+		if req.Header.Get(key) == "" {
+			req.Header[key] = values
+		}
+	}
+}
+
+// Token returns the current token of the client
+func (s clientToken) Token(ctx context.Context) (*Token, error) {
+	return s.c.currentToken(ctx)
+}
+
+// renew renews the token of the client
+func (s clientToken) renew(ctx context.Context, stale string) (*Token, error) {
+	return s.c.renewToken(ctx, stale)
+}
@@ -0,0 +1,59 @@
+package paypal
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAuthAssertion(t *testing.T) {
+	parts := strings.Split(AuthAssertion("partner", "MERCHANT1"), ".")
+	if len(parts) != 3 || parts[2] != "" {
+		t.Fatalf("expected an unsigned JWT, got %v", parts)
+	}
+	header, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if string(header) != `{"alg":"none"}` || string(payload) != `{"iss":"partner","payer_id":"MERCHANT1"}` {
+		t.Fatalf("unexpected JWT %s.%s", header, payload)
+	}
+}
+
+func TestForMerchant(t *testing.T) {
+	var fetches int32
+	var assertions, attributions []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oauth2/token" {
+			atomic.AddInt32(&fetches, 1)
+			w.Write([]byte(`{"access_token":"partner-token","expires_in":32400}`))
+			return
+		}
+		assertions = append(assertions, r.Header.Get(HeaderAuthAssertion))
+		attributions = append(attributions, r.Header.Get(HeaderPartnerAttributionID))
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("partner", "secret", ts.URL)
+	c.SetPartnerAttributionID("BN-1")
+	m1, m2 := c.ForMerchant("MERCHANT1"), c.ForMerchant("MERCHANT2")
+
+	m1.GetOrder("O-1")
+	m2.CaptureOrder("O-2", CaptureOrderRequest{}, WithPartnerAttributionID("BN-2"))
+	c.GetOrder("O-3")
+
+	expected := []string{AuthAssertion("partner", "MERCHANT1"), AuthAssertion("partner", "MERCHANT2"), ""}
+	for i := range expected {
+		if assertions[i] != expected[i] {
+			t.Errorf("request %d: expected assertion %q, got %q", i, expected[i], assertions[i])
+		}
+	}
+	if strings.Join(attributions, ",") != "BN-1,BN-2,BN-1" {
+		t.Errorf("unexpected attribution IDs %v", attributions)
+	}
+	if fetches != 1 {
+		t.Errorf("expected merchant clients to share the partner token, got %d fetches", fetches)
+	}
+}
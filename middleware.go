@@ -0,0 +1,77 @@
+package paypal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+type (
+	// Handler sends a request to PayPal. Its error is an *ErrorResponse, already
+	// decoded, when PayPal answers with a non-2xx status; the response is then
+	// returned as well, with its body still readable
+	Handler func(req *http.Request) (*http.Response, error)
+
+	// Middleware wraps the Handler which sends the requests of a Client, e.g. to add
+	// headers, record metrics or serve responses from a cache. It may change the
+	// request, the response and the error, or answer without calling next.
+	//
+	// The response body of a 2xx answer is decoded by Send once the chain returns
+	Middleware func(next Handler) Handler
+)
+
+// errNoResponse is returned when the middlewares return neither a response nor an error
+var errNoResponse = errors.New("paypal: middleware returned neither a response nor an error")
+
+// Use appends middlewares to the chain wrapping every request sent by c, token
+// requests included. The first middleware is the outermost: it sees the request
+// first and the response last. Retries and rate limiting happen inside the chain
+func (c *Client) Use(middlewares ...Middleware) {
+	c.middlewareMu.Lock()
+	defer c.middlewareMu.Unlock()
+
+	// Sends in progress keep the chain they started with
+	c.middlewares = append(c.middlewares[:len(c.middlewares):len(c.middlewares)], middlewares...)
+}
+
+// handler returns the chain of middlewares around c.send
+func (c *Client) handler() Handler {
+	c.middlewareMu.Lock()
+	middlewares := c.middlewares
+	c.middlewareMu.Unlock()
+
+	h := Handler(c.send)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return func(req *http.Request) (*http.Response, error) {
+		resp, err := h(req)
+		if resp == nil && err == nil {
+			return nil, errNoResponse
+		}
+		return resp, err
+	}
+}
+
+// send sends req and decodes the ErrorResponse of a non-2xx answer
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return resp, nil
+	}
+
+	errResp := &ErrorResponse{Response: resp}
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err == nil && len(data) > 0 {
+		json.Unmarshal(data, errResp)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	return resp, errResp
+}
@@ -0,0 +1,140 @@
+package paypal
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if r.Header.Get("Traceparent") == "" {
+			t.Errorf("expected the header set by the middleware")
+		}
+		if r.URL.Path == "/v2/checkout/orders/MISSING" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"name":"RESOURCE_NOT_FOUND","message":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"O-1"}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	var calls []string
+	var errResp *ErrorResponse
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" request")
+				resp, err := next(req)
+				calls = append(calls, name+" response")
+				return resp, err
+			}
+		}
+	}
+	c.Use(record("outer"), func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("Traceparent", "00-trace-span-01")
+			resp, err := next(req)
+			errors.As(err, &errResp)
+			return resp, err
+		}
+	})
+	c.Use(record("inner"))
+
+	order, err := c.GetOrder("O-1")
+	if err != nil || order.ID != "O-1" {
+		t.Fatalf("unexpected order %+v, error %v", order, err)
+	}
+	expected := []string{"outer request", "inner request", "inner response", "outer response"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+
+	_, err = c.GetOrder("MISSING")
+	if !errors.Is(err, ErrResourceNotFound) {
+		t.Fatalf("expected RESOURCE_NOT_FOUND, got %v", err)
+	}
+	if errResp == nil || errResp.Message != "not found" || errResp.Response.StatusCode != http.StatusNotFound {
+		t.Errorf("expected the middleware to see the decoded error, got %+v", errResp)
+	}
+	if hits != 2 {
+		t.Errorf("expected 2 requests, got %d", hits)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	// Serves orders from a cache, and fails everything else
+	c.Use(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if strings.HasPrefix(req.URL.Path, "/v2/checkout/orders/") {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"id":"CACHED"}`))),
+					Request:    req,
+				}, nil
+			}
+			if req.URL.Path == "/v2/payments/refunds/R-1" {
+				return nil, nil
+			}
+			return nil, errors.New("chaos")
+		}
+	})
+
+	order, err := c.GetOrder("O-1")
+	if err != nil || order.ID != "CACHED" {
+		t.Fatalf("expected the cached order, got %+v, error %v", order, err)
+	}
+	if _, err := c.GetCapture("C-1"); err == nil || err.Error() != "chaos" {
+		t.Fatalf("expected the injected error, got %v", err)
+	}
+	if _, err := c.GetRefund("R-1"); err != errNoResponse {
+		t.Fatalf("expected errNoResponse without a response, got %v", err)
+	}
+}
+
+func TestMiddlewareForMerchant(t *testing.T) {
+	var merchant int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+	c.Use(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(HeaderAuthAssertion) != "" {
+				atomic.AddInt32(&merchant, 1)
+			}
+			return next(req)
+		}
+	})
+
+	if _, err := c.ForMerchant("MERCHANT").GetOrder("O-1"); err != nil {
+		t.Fatal(err)
+	}
+	if merchant != 1 {
+		t.Errorf("expected the merchant client to use the middlewares of c")
+	}
+}
@@ -0,0 +1,75 @@
+package paypal
+
+import (
+	"errors"
+
+	"github.com/siriele/paypal/money"
+)
+
+// errNilAmount is returned when converting a nil amount struct
+var errNilAmount = errors.New("paypal: nil amount")
+
+// NewMoney returns a as a Money
+func NewMoney(a money.Amount) *Money {
+	return &Money{Currency: a.Currency(), Value: a.Value()}
+}
+
+// NewAmount returns a as an Amount, without breakdown
+func NewAmount(a money.Amount) *Amount {
+	return &Amount{Currency: a.Currency(), Value: a.Value()}
+}
+
+// NewPurchaseUnitAmount returns a as a PurchaseUnitAmount, without breakdown
+func NewPurchaseUnitAmount(a money.Amount) *PurchaseUnitAmount {
+	return &PurchaseUnitAmount{Currency: a.Currency(), Value: a.Value()}
+}
+
+// NewAmountPayout returns a as an AmountPayout
+func NewAmountPayout(a money.Amount) *AmountPayout {
+	return &AmountPayout{Currency: a.Currency(), Value: a.Value()}
+}
+
+// NewCurrency returns a as a Currency
+func NewCurrency(a money.Amount) *Currency {
+	return &Currency{Currency: a.Currency(), Value: a.Value()}
+}
+
+// Decimal parses m into an exact money.Amount
+func (m *Money) Decimal() (money.Amount, error) {
+	if m == nil {
+		return money.Amount{}, errNilAmount
+	}
+	return money.Parse(m.Value, m.Currency)
+}
+
+// Decimal parses a into an exact money.Amount, the breakdown is ignored
+func (a *Amount) Decimal() (money.Amount, error) {
+	if a == nil {
+		return money.Amount{}, errNilAmount
+	}
+	return money.Parse(a.Value, a.Currency)
+}
+
+// Decimal parses a into an exact money.Amount, the breakdown is ignored
+func (a *PurchaseUnitAmount) Decimal() (money.Amount, error) {
+	if a == nil {
+		return money.Amount{}, errNilAmount
+	}
+	return money.Parse(a.Value, a.Currency)
+}
+
+// Decimal parses a into an exact money.Amount
+func (a *AmountPayout) Decimal() (money.Amount, error) {
+	if a == nil {
+		return money.Amount{}, errNilAmount
+	}
+	return money.Parse(a.Value, a.Currency)
+}
+
+// Decimal parses c into an exact money.Amount
+func (c *Currency) Decimal() (money.Amount, error) {
+	if c == nil {
+		return money.Amount{}, errNilAmount
+	}
+	return money.Parse(c.Value, c.Currency)
+}
@@ -0,0 +1,60 @@
+package money
+
+// decimals lists the currencies which do not have two decimals.
+//
+// It follows ISO 4217, except for HUF and TWD which have two decimals in
+// ISO 4217 but are integers for PayPal
+var decimals = map[string]int{
+	"BIF": 0,
+	"CLP": 0,
+	"DJF": 0,
+	"GNF": 0,
+	"HUF": 0,
+	"ISK": 0,
+	"JPY": 0,
+	"KMF": 0,
+	"KRW": 0,
+	"PYG": 0,
+	"RWF": 0,
+	"TWD": 0,
+	"UGX": 0,
+	"UYI": 0,
+	"VND": 0,
+	"VUV": 0,
+	"XAF": 0,
+	"XOF": 0,
+	"XPF": 0,
+
+	"BHD": 3,
+	"IQD": 3,
+	"JOD": 3,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"TND": 3,
+
+	"CLF": 4,
+	"UYW": 4,
+}
+
+// Decimals returns the number of decimals PayPal accepts for currency,
+// e.g. 2 for USD and 0 for JPY. Unknown currencies have 2 decimals
+func Decimals(currency string) int {
+	if d, ok := decimals[currency]; ok {
+		return d
+	}
+	return 2
+}
+
+// validCurrency reports whether currency looks like an ISO 4217 code
+func validCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for i := 0; i < len(currency); i++ {
+		if currency[i] < 'A' || currency[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,315 @@
+// Package money provides exact decimal amounts of money, aware of the
+// number of decimals of each currency, for use with the PayPal API.
+//
+//	price, err := money.Parse("12.5", "USD")
+//	total, err := price.Mul(3)   // 37.50 USD
+//	shares, err := total.Split(2) // 18.75 USD, 18.75 USD
+//	total.Value()                // "37.50", as PayPal expects it
+//
+// Amounts are stored as an integer number of minor units, e.g. cents,
+// so arithmetic never rounds
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var (
+	// ErrInvalidAmount is returned when parsing a malformed value
+	ErrInvalidAmount = errors.New("money: invalid amount")
+	// ErrInvalidCurrency is returned for a currency which is not a 3 letter code
+	ErrInvalidCurrency = errors.New("money: invalid currency")
+	// ErrPrecision is returned when a value has more decimals than its currency
+	ErrPrecision = errors.New("money: too many decimals for currency")
+	// ErrCurrencyMismatch is returned when combining amounts of different currencies
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	// ErrOverflow is returned when an amount does not fit in 64 bits of minor units
+	ErrOverflow = errors.New("money: amount overflows")
+)
+
+// Amount is an amount of money in a currency.
+//
+// The zero Amount is zero in no currency, it takes the currency of the
+// amount it is added to, so sums can start from it
+type Amount struct {
+	units    int64
+	currency string
+}
+
+// New returns the amount of minor units of currency, e.g. New(1250, "USD") is 12.50 USD
+func New(units int64, currency string) (Amount, error) {
+	if !validCurrency(currency) {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+	return Amount{units: units, currency: currency}, nil
+}
+
+// Zero returns zero in currency
+func Zero(currency string) (Amount, error) {
+	return New(0, currency)
+}
+
+// Parse parses a value as PayPal formats it, e.g. "12.50" or "-3".
+// Trailing zeros beyond the decimals of currency are accepted, other
+// decimals are an error rather than being rounded
+func Parse(value, currency string) (Amount, error) {
+	if !validCurrency(currency) {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+
+	s, negative := value, false
+	if strings.HasPrefix(s, "-") {
+		s, negative = s[1:], true
+	}
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+		if frac == "" {
+			return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+		}
+	}
+	if (whole == "" && frac == "") || !digits(whole) || !digits(frac) {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	d := Decimals(currency)
+	if len(frac) > d {
+		if strings.Trim(frac[d:], "0") != "" {
+			return Amount{}, fmt.Errorf("%w: %q has more than %d decimals for %s", ErrPrecision, value, d, currency)
+		}
+		frac = frac[:d]
+	}
+	frac += strings.Repeat("0", d-len(frac))
+
+	units, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok {
+		units = new(big.Int)
+	}
+	if negative {
+		units.Neg(units)
+	}
+	if !units.IsInt64() {
+		return Amount{}, fmt.Errorf("%w: %q", ErrOverflow, value)
+	}
+
+	return Amount{units: units.Int64(), currency: currency}, nil
+}
+
+// MustParse is like Parse but panics on error. Use it for constants
+func MustParse(value, currency string) Amount {
+	a, err := Parse(value, currency)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Currency returns the currency code of a, e.g. "USD"
+func (a Amount) Currency() string {
+	return a.currency
+}
+
+// MinorUnits returns a in minor units of its currency, e.g. 1250 for 12.50 USD
+func (a Amount) MinorUnits() int64 {
+	return a.units
+}
+
+// Value formats a with the decimals of its currency, as PayPal expects it, e.g. "12.50"
+func (a Amount) Value() string {
+	d := Decimals(a.currency)
+	units := new(big.Int).SetInt64(a.units)
+
+	sign := ""
+	if units.Sign() < 0 {
+		sign = "-"
+		units.Neg(units)
+	}
+	s := units.String()
+	if d == 0 {
+		return sign + s
+	}
+	if len(s) <= d {
+		s = strings.Repeat("0", d-len(s)+1) + s
+	}
+	return sign + s[:len(s)-d] + "." + s[len(s)-d:]
+}
+
+// String returns a and its currency, e.g. "12.50 USD"
+func (a Amount) String() string {
+	if a.currency == "" {
+		return a.Value()
+	}
+	return a.Value() + " " + a.currency
+}
+
+// IsZero reports whether a is zero
+func (a Amount) IsZero() bool {
+	return a.units == 0
+}
+
+// Sign returns -1, 0 or 1 as a is negative, zero or positive
+func (a Amount) Sign() int {
+	switch {
+	case a.units < 0:
+		return -1
+	case a.units > 0:
+		return 1
+	}
+	return 0
+}
+
+// Equal reports whether a and b are the same amount in the same currency
+func (a Amount) Equal(b Amount) bool {
+	return a.units == b.units && (a.currency == b.currency || a.units == 0 && (a.currency == "" || b.currency == ""))
+}
+
+// Cmp compares a and b, returning -1, 0 or 1 as a is less than, equal to or greater than b
+func (a Amount) Cmp(b Amount) (int, error) {
+	if _, err := a.common(b); err != nil {
+		return 0, err
+	}
+	switch {
+	case a.units < b.units:
+		return -1, nil
+	case a.units > b.units:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Add returns a+b
+func (a Amount) Add(b Amount) (Amount, error) {
+	currency, err := a.common(b)
+	if err != nil {
+		return Amount{}, err
+	}
+	sum := a.units + b.units
+	if (sum > a.units) != (b.units > 0) {
+		return Amount{}, fmt.Errorf("%w: %s + %s", ErrOverflow, a, b)
+	}
+	return Amount{units: sum, currency: currency}, nil
+}
+
+// Sub returns a-b
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if b.units == math.MinInt64 {
+		return Amount{}, fmt.Errorf("%w: %s - %s", ErrOverflow, a, b)
+	}
+	return a.Add(b.Neg())
+}
+
+// Mul returns a multiplied by n, e.g. a unit price by a quantity
+func (a Amount) Mul(n int64) (Amount, error) {
+	p := new(big.Int).Mul(big.NewInt(a.units), big.NewInt(n))
+	if !p.IsInt64() {
+		return Amount{}, fmt.Errorf("%w: %s * %d", ErrOverflow, a, n)
+	}
+	return Amount{units: p.Int64(), currency: a.currency}, nil
+}
+
+// Neg returns -a
+func (a Amount) Neg() Amount {
+	return Amount{units: -a.units, currency: a.currency}
+}
+
+// Abs returns the absolute value of a
+func (a Amount) Abs() Amount {
+	if a.units < 0 {
+		return a.Neg()
+	}
+	return a
+}
+
+// Allocate splits a in parts proportional to ratios without losing a minor unit:
+// the remainder is spread one minor unit at a time over the first parts.
+// Allocate(1, 1, 1) of 10.00 USD is 3.34, 3.33 and 3.33 USD
+func (a Amount) Allocate(ratios ...int64) ([]Amount, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("money: no ratios to allocate")
+	}
+	total := new(big.Int)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, fmt.Errorf("money: negative ratio %d", r)
+		}
+		total.Add(total, big.NewInt(r))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("money: ratios sum to zero")
+	}
+
+	units := big.NewInt(a.units)
+	parts := make([]Amount, len(ratios))
+	remainder := a.units
+	for i, r := range ratios {
+		share := new(big.Int).Mul(units, big.NewInt(r))
+		share.Quo(share, total) // truncates towards zero, for negative amounts too
+		parts[i] = Amount{units: share.Int64(), currency: a.currency}
+		remainder -= share.Int64()
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].units += step
+		remainder -= step
+	}
+
+	return parts, nil
+}
+
+// Split splits a in n parts as equal as possible, see Allocate
+func (a Amount) Split(n int) ([]Amount, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("money: cannot split in %d parts", n)
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return a.Allocate(ratios...)
+}
+
+// Sum returns the sum of amounts, which must all be in the same currency
+func Sum(amounts ...Amount) (Amount, error) {
+	var total Amount
+	for _, a := range amounts {
+		var err error
+		if total, err = total.Add(a); err != nil {
+			return Amount{}, err
+		}
+	}
+	return total, nil
+}
+
+// common returns the currency of a and b, the zero Amount adopting the other currency
+func (a Amount) common(b Amount) (string, error) {
+	switch {
+	case a.currency == b.currency:
+		return a.currency, nil
+	case a.currency == "" && a.units == 0:
+		return b.currency, nil
+	case b.currency == "" && b.units == 0:
+		return a.currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.currency, b.currency)
+}
+
+// digits reports whether s only has decimal digits
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
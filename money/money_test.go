@@ -0,0 +1,113 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value, currency string
+		units           int64
+		formatted       string
+		err             error
+	}{
+		{"12.5", "USD", 1250, "12.50", nil},
+		{"12", "USD", 1200, "12.00", nil},
+		{".5", "EUR", 50, "0.50", nil},
+		{"-3.07", "USD", -307, "-3.07", nil},
+		{"0.001", "USD", 0, "", ErrPrecision},
+		{"10.500", "USD", 1050, "10.50", nil},
+		{"1000", "JPY", 1000, "1000", nil},
+		{"1000.00", "JPY", 1000, "1000", nil},
+		{"1000.5", "JPY", 0, "", ErrPrecision},
+		{"1.234", "KWD", 1234, "1.234", nil},
+		{"1,00", "USD", 0, "", ErrInvalidAmount},
+		{"1.", "USD", 0, "", ErrInvalidAmount},
+		{"1e3", "USD", 0, "", ErrInvalidAmount},
+		{"", "USD", 0, "", ErrInvalidAmount},
+		{"1", "usd", 0, "", ErrInvalidCurrency},
+		{"99999999999999999999", "USD", 0, "", ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		a, err := Parse(tt.value, tt.currency)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q, %q): expected error %v, got %v", tt.value, tt.currency, tt.err, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if a.MinorUnits() != tt.units || a.Value() != tt.formatted || a.Currency() != tt.currency {
+			t.Errorf("Parse(%q, %q): got %d minor units formatted %q", tt.value, tt.currency, a.MinorUnits(), a.Value())
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	price := MustParse("19.99", "USD")
+
+	total, err := price.Mul(3)
+	if err != nil || total.Value() != "59.97" {
+		t.Fatalf("unexpected total %s, %v", total, err)
+	}
+
+	total, err = total.Sub(MustParse("60", "USD"))
+	if err != nil || total.String() != "-0.03 USD" || total.Sign() != -1 {
+		t.Fatalf("unexpected difference %s, %v", total, err)
+	}
+
+	if _, err = price.Add(MustParse("1", "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected currency mismatch, got %v", err)
+	}
+	max, err := New(math.MaxInt64, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = max.Add(MustParse("0.01", "USD")); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected overflow, got %v", err)
+	}
+
+	sum, err := Sum(MustParse("0.10", "USD"), MustParse("0.20", "USD"))
+	if err != nil || !sum.Equal(MustParse("0.3", "USD")) {
+		t.Fatalf("unexpected sum %s, %v", sum, err)
+	}
+
+	if c, err := price.Cmp(sum); err != nil || c != 1 {
+		t.Fatalf("expected %s > %s, got %d, %v", price, sum, c, err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		ratios []int64
+		parts  string
+	}{
+		{MustParse("10", "USD"), []int64{1, 1, 1}, "[3.34 USD 3.33 USD 3.33 USD]"},
+		{MustParse("0.05", "USD"), []int64{3, 7}, "[0.02 USD 0.03 USD]"},
+		{MustParse("100", "JPY"), []int64{1, 2}, "[34 JPY 66 JPY]"},
+		{MustParse("-10", "USD"), []int64{1, 1, 1}, "[-3.34 USD -3.33 USD -3.33 USD]"},
+		{MustParse("1", "USD"), []int64{0, 1, 1}, "[0.00 USD 0.50 USD 0.50 USD]"},
+	}
+
+	for _, tt := range tests {
+		parts, err := tt.amount.Allocate(tt.ratios...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(parts) != tt.parts {
+			t.Errorf("%s allocated %v: expected %s, got %v", tt.amount, tt.ratios, tt.parts, parts)
+		}
+		if sum, _ := Sum(parts...); !sum.Equal(tt.amount) {
+			t.Errorf("%s allocated %v: parts sum to %s", tt.amount, tt.ratios, sum)
+		}
+	}
+
+	if _, err := MustParse("1", "USD").Split(0); err == nil {
+		t.Fatalf("expected an error splitting in 0 parts")
+	}
+}
@@ -1,13 +1,21 @@
 package paypal
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // GetOrder retrieves order by ID
 // Endpoint: GET /v2/checkout/orders/ID
 func (c *Client) GetOrder(orderID string) (*Order, error) {
+	return c.GetOrderContext(context.Background(), orderID)
+}
+
+// GetOrderContext is like GetOrder but uses ctx for the request
+func (c *Client) GetOrderContext(ctx context.Context, orderID string) (*Order, error) {
 	order := &Order{}
 
-	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s%s", c.APIBase, "/v2/checkout/orders/", orderID), nil)
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s%s", c.APIBase, "/v2/checkout/orders/", orderID), nil)
 	if err != nil {
 		return order, err
 	}
@@ -21,20 +29,19 @@ func (c *Client) GetOrder(orderID string) (*Order, error) {
 
 // CreateOrder - Use this call to create an order
 // Endpoint: POST /v2/checkout/orders
-func (c *Client) CreateOrder(intent PaymentIntent, purchaseUnits []PurchaseUnitRequest, payer *CreateOrderPayer, appContext *ApplicationContext) (*Order, error) {
-	type createOrderRequest struct {
-		Intent             PaymentIntent         `json:"intent"`
-		Payer              *CreateOrderPayer     `json:"payer,omitempty"`
-		PurchaseUnits      []PurchaseUnitRequest `json:"purchase_units"`
-		ApplicationContext *ApplicationContext   `json:"application_context,omitempty"`
-	}
+func (c *Client) CreateOrder(intent PaymentIntent, purchaseUnits []PurchaseUnitRequest, payer *CreateOrderPayer, appContext *ApplicationContext, opts ...RequestOption) (*Order, error) {
+	return c.CreateOrderContext(context.Background(), intent, purchaseUnits, payer, appContext, opts...)
+}
 
+// CreateOrderContext is like CreateOrder but uses ctx for the request
+func (c *Client) CreateOrderContext(ctx context.Context, intent PaymentIntent, purchaseUnits []PurchaseUnitRequest, payer *CreateOrderPayer, appContext *ApplicationContext, opts ...RequestOption) (*Order, error) {
 	order := &Order{}
 
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/checkout/orders"), createOrderRequest{Intent: intent, PurchaseUnits: purchaseUnits, Payer: payer, ApplicationContext: appContext})
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/checkout/orders"), CreateOrderRequest{Intent: intent, PurchaseUnits: purchaseUnits, Payer: payer, ApplicationContext: appContext})
 	if err != nil {
 		return order, err
 	}
+	c.applyIdempotency(req, opts)
 
 	if err = c.SendWithAuth(req, order); err != nil {
 		return order, err
@@ -46,8 +53,13 @@ func (c *Client) CreateOrder(intent PaymentIntent, purchaseUnits []PurchaseUnitR
 // UpdateOrder updates the order by ID
 // Endpoint: PATCH /v2/checkout/orders/ID
 func (c *Client) UpdateOrder(orderID string, orderUpdate []PaymentPatch) error {
+	return c.UpdateOrderContext(context.Background(), orderID, orderUpdate)
+}
+
+// UpdateOrderContext is like UpdateOrder but uses ctx for the request
+func (c *Client) UpdateOrderContext(ctx context.Context, orderID string, orderUpdate []PaymentPatch) error {
 
-	req, err := c.NewRequest("PATCH", fmt.Sprintf("%s%s%s", c.APIBase, "/v2/checkout/orders/", orderID), orderUpdate)
+	req, err := c.NewRequestContext(ctx, "PATCH", fmt.Sprintf("%s%s%s", c.APIBase, "/v2/checkout/orders/", orderID), orderUpdate)
 	if err != nil {
 		return err
 	}
@@ -61,13 +73,19 @@ func (c *Client) UpdateOrder(orderID string, orderUpdate []PaymentPatch) error {
 
 // AuthorizeOrder - https://developer.paypal.com/docs/api/orders/v2/#orders_authorize
 // Endpoint: POST /v2/checkout/orders/ID/authorize
-func (c *Client) AuthorizeOrder(orderID string, authorizeOrderRequest AuthorizeOrderRequest) (*AuthorizeOrderResponse, error) {
+func (c *Client) AuthorizeOrder(orderID string, authorizeOrderRequest AuthorizeOrderRequest, opts ...RequestOption) (*AuthorizeOrderResponse, error) {
+	return c.AuthorizeOrderContext(context.Background(), orderID, authorizeOrderRequest, opts...)
+}
+
+// AuthorizeOrderContext is like AuthorizeOrder but uses ctx for the request
+func (c *Client) AuthorizeOrderContext(ctx context.Context, orderID string, authorizeOrderRequest AuthorizeOrderRequest, opts ...RequestOption) (*AuthorizeOrderResponse, error) {
 	auth := &AuthorizeOrderResponse{}
 
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/checkout/orders/"+orderID+"/authorize"), authorizeOrderRequest)
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/checkout/orders/"+orderID+"/authorize"), authorizeOrderRequest)
 	if err != nil {
 		return auth, err
 	}
+	c.applyIdempotency(req, opts)
 
 	if err = c.SendWithAuth(req, auth); err != nil {
 		return auth, err
@@ -78,13 +96,19 @@ func (c *Client) AuthorizeOrder(orderID string, authorizeOrderRequest AuthorizeO
 
 // CaptureOrder - https://developer.paypal.com/docs/api/orders/v2/#orders_capture
 // Endpoint: POST /v2/checkout/orders/ID/capture
-func (c *Client) CaptureOrder(orderID string, captureOrderRequest CaptureOrderRequest) (*CaptureOrderResponse, error) {
+func (c *Client) CaptureOrder(orderID string, captureOrderRequest CaptureOrderRequest, opts ...RequestOption) (*CaptureOrderResponse, error) {
+	return c.CaptureOrderContext(context.Background(), orderID, captureOrderRequest, opts...)
+}
+
+// CaptureOrderContext is like CaptureOrder but uses ctx for the request
+func (c *Client) CaptureOrderContext(ctx context.Context, orderID string, captureOrderRequest CaptureOrderRequest, opts ...RequestOption) (*CaptureOrderResponse, error) {
 	capture := &CaptureOrderResponse{}
 
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/checkout/orders/"+orderID+"/capture"), captureOrderRequest)
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/checkout/orders/"+orderID+"/capture"), captureOrderRequest)
 	if err != nil {
 		return capture, err
 	}
+	c.applyIdempotency(req, opts)
 
 	if err = c.SendWithAuth(req, capture); err != nil {
 		return capture, err
@@ -92,3 +116,82 @@ func (c *Client) CaptureOrder(orderID string, captureOrderRequest CaptureOrderRe
 
 	return capture, nil
 }
+
+// ConfirmPaymentSource confirms the payer's intent to pay the order with a payment source,
+// e.g. a card, before it is authorized or captured
+// Endpoint: POST /v2/checkout/orders/ID/confirm-payment-source
+func (c *Client) ConfirmPaymentSource(orderID string, confirmRequest ConfirmPaymentSourceRequest, opts ...RequestOption) (*Order, error) {
+	return c.ConfirmPaymentSourceContext(context.Background(), orderID, confirmRequest, opts...)
+}
+
+// ConfirmPaymentSourceContext is like ConfirmPaymentSource but uses ctx for the request
+func (c *Client) ConfirmPaymentSourceContext(ctx context.Context, orderID string, confirmRequest ConfirmPaymentSourceRequest, opts ...RequestOption) (*Order, error) {
+	order := &Order{}
+
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/checkout/orders/"+orderID+"/confirm-payment-source"), confirmRequest)
+	if err != nil {
+		return order, err
+	}
+	c.applyIdempotency(req, opts)
+
+	if err = c.SendWithAuth(req, order); err != nil {
+		return order, err
+	}
+
+	return order, nil
+}
+
+// AddTracking adds the tracking information of a shipment to a captured order
+// Endpoint: POST /v2/checkout/orders/ID/track
+func (c *Client) AddTracking(orderID string, tracker OrderTrackerRequest, opts ...RequestOption) (*Order, error) {
+	return c.AddTrackingContext(context.Background(), orderID, tracker, opts...)
+}
+
+// AddTrackingContext is like AddTracking but uses ctx for the request
+func (c *Client) AddTrackingContext(ctx context.Context, orderID string, tracker OrderTrackerRequest, opts ...RequestOption) (*Order, error) {
+	order := &Order{}
+
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/checkout/orders/"+orderID+"/track"), tracker)
+	if err != nil {
+		return order, err
+	}
+	c.applyIdempotency(req, opts)
+
+	if err = c.SendWithAuth(req, order); err != nil {
+		return order, err
+	}
+
+	return order, nil
+}
+
+// PatchAmount returns the operation replacing the amount of the purchase unit
+// referenceID, an empty referenceID is the default purchase unit
+func PatchAmount(referenceID string, amount *PurchaseUnitAmount) PaymentPatch {
+	return PaymentPatch{Operation: "replace", Path: purchaseUnitPath(referenceID) + "/amount", Value: amount}
+}
+
+// PatchShippingAddress returns the operation setting the shipping address of the purchase unit referenceID
+func PatchShippingAddress(referenceID string, address *ShippingDetailAddressPortable) PaymentPatch {
+	return PaymentPatch{Operation: "replace", Path: purchaseUnitPath(referenceID) + "/shipping/address", Value: address}
+}
+
+// PatchInvoiceID returns the operation setting the invoice ID of the purchase unit referenceID,
+// an empty invoiceID removes it
+func PatchInvoiceID(referenceID, invoiceID string) PaymentPatch {
+	return patchString(purchaseUnitPath(referenceID)+"/invoice_id", invoiceID)
+}
+
+// PatchCustomID returns the operation setting the custom ID of the purchase unit referenceID,
+// an empty customID removes it
+func PatchCustomID(referenceID, customID string) PaymentPatch {
+	return patchString(purchaseUnitPath(referenceID)+"/custom_id", customID)
+}
+
+// patchString returns an add operation for value, or a remove operation when it is empty.
+// Adding a field which is already set replaces it
+func patchString(path, value string) PaymentPatch {
+	if value == "" {
+		return PaymentPatch{Operation: "remove", Path: path}
+	}
+	return PaymentPatch{Operation: "add", Path: path, Value: value}
+}
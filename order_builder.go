@@ -0,0 +1,344 @@
+package paypal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/siriele/paypal/money"
+)
+
+type (
+	// OrderBuilder assembles a CreateOrderRequest, computing the breakdown
+	// of every purchase unit from its items:
+	//
+	//	order, err := paypal.NewOrderBuilder(paypal.IntentCapture, "USD").
+	//		AddItem("T-Shirt", 2, money.MustParse("7.50", "USD")).
+	//		Shipping(money.MustParse("4.99", "USD")).
+	//		ShipTo("John Doe", paypal.ShippingDetailAddressPortable{CountryCode: "US", PostalCode: "95131"}).
+	//		Create(ctx, c)
+	//
+	// Methods apply to the current purchase unit, PurchaseUnit starts a new one.
+	// The first error is kept and returned by Build
+	OrderBuilder struct {
+		currency string
+		request  CreateOrderRequest
+		units    []*unitBuilder
+		err      error
+	}
+
+	// unitBuilder is a purchase unit being built
+	unitBuilder struct {
+		PurchaseUnitRequest
+		// touched is set once a method of OrderBuilder applied to the unit
+		touched          bool
+		amount           *money.Amount
+		tax              money.Amount
+		shipping         money.Amount
+		handling         money.Amount
+		insurance        money.Amount
+		shippingDiscount money.Amount
+		discount         money.Amount
+	}
+)
+
+// NewOrderBuilder returns an OrderBuilder for an order with intent, whose amounts are in currency
+func NewOrderBuilder(intent PaymentIntent, currency string) *OrderBuilder {
+	return &OrderBuilder{
+		currency: currency,
+		request:  CreateOrderRequest{Intent: intent},
+		units:    []*unitBuilder{{}},
+	}
+}
+
+// PurchaseUnit starts a new purchase unit identified by referenceID,
+// or names the current one if nothing was added to it yet
+func (b *OrderBuilder) PurchaseUnit(referenceID string) *OrderBuilder {
+	u := b.units[len(b.units)-1]
+	if u.ReferenceID != "" || u.touched {
+		u = &unitBuilder{}
+		b.units = append(b.units, u)
+	}
+	u.ReferenceID = referenceID
+	return b
+}
+
+// Description sets the description of the purchase unit
+func (b *OrderBuilder) Description(description string) *OrderBuilder {
+	b.unit().Description = description
+	return b
+}
+
+// CustomID sets the API caller-provided external ID of the purchase unit
+func (b *OrderBuilder) CustomID(customID string) *OrderBuilder {
+	b.unit().CustomID = customID
+	return b
+}
+
+// InvoiceID sets the invoice ID of the purchase unit
+func (b *OrderBuilder) InvoiceID(invoiceID string) *OrderBuilder {
+	b.unit().InvoiceID = invoiceID
+	return b
+}
+
+// SoftDescriptor sets the text shown on the payer's card statement
+func (b *OrderBuilder) SoftDescriptor(softDescriptor string) *OrderBuilder {
+	b.unit().SoftDescriptor = softDescriptor
+	return b
+}
+
+// AddItem adds quantity items of unitAmount to the purchase unit
+func (b *OrderBuilder) AddItem(name string, quantity int64, unitAmount money.Amount) *OrderBuilder {
+	return b.AddItemDetails(Item{Name: name, Quantity: strconv.FormatInt(quantity, 10), UnitAmount: NewMoney(unitAmount)})
+}
+
+// AddItemDetails adds item to the purchase unit, for items with a SKU,
+// a category or a tax. Its unit amount and tax are added to the breakdown
+func (b *OrderBuilder) AddItemDetails(item Item) *OrderBuilder {
+	u := b.unit()
+	u.Items = append(u.Items, item)
+	return b
+}
+
+// Amount sets the amount of a purchase unit without items,
+// the amount of a purchase unit with items is the sum of its breakdown
+func (b *OrderBuilder) Amount(amount money.Amount) *OrderBuilder {
+	if b.checkCurrency(amount) {
+		b.unit().amount = &amount
+	}
+	return b
+}
+
+// Tax adds tax to the tax total of the purchase unit, on top of the taxes of the items
+func (b *OrderBuilder) Tax(tax money.Amount) *OrderBuilder {
+	u := b.unit()
+	u.tax = b.add(u.tax, tax)
+	return b
+}
+
+// Shipping adds shipping fees to the purchase unit
+func (b *OrderBuilder) Shipping(shipping money.Amount) *OrderBuilder {
+	u := b.unit()
+	u.shipping = b.add(u.shipping, shipping)
+	return b
+}
+
+// Handling adds handling fees to the purchase unit
+func (b *OrderBuilder) Handling(handling money.Amount) *OrderBuilder {
+	u := b.unit()
+	u.handling = b.add(u.handling, handling)
+	return b
+}
+
+// Insurance adds insurance fees to the purchase unit
+func (b *OrderBuilder) Insurance(insurance money.Amount) *OrderBuilder {
+	u := b.unit()
+	u.insurance = b.add(u.insurance, insurance)
+	return b
+}
+
+// ShippingDiscount adds a discount on the shipping fees of the purchase unit
+func (b *OrderBuilder) ShippingDiscount(discount money.Amount) *OrderBuilder {
+	u := b.unit()
+	u.shippingDiscount = b.add(u.shippingDiscount, discount)
+	return b
+}
+
+// Discount adds a discount on the items of the purchase unit
+func (b *OrderBuilder) Discount(discount money.Amount) *OrderBuilder {
+	u := b.unit()
+	u.discount = b.add(u.discount, discount)
+	return b
+}
+
+// ShipTo sets the name and address the purchase unit is shipped to
+func (b *OrderBuilder) ShipTo(fullName string, address ShippingDetailAddressPortable) *OrderBuilder {
+	b.unit().Shipping = &ShippingDetail{Name: &Name{FullName: fullName}, Address: &address}
+	return b
+}
+
+// Payee sets the merchant who receives the payment of the purchase unit
+func (b *OrderBuilder) Payee(payee PayeeForOrders) *OrderBuilder {
+	b.unit().Payee = &payee
+	return b
+}
+
+// PlatformFee adds a fee the platform collects on the purchase unit.
+// payee may be nil, the fee then goes to the API caller
+func (b *OrderBuilder) PlatformFee(fee money.Amount, payee *PayeeForOrders) *OrderBuilder {
+	if !b.checkCurrency(fee) {
+		return b
+	}
+	u := b.unit()
+	if u.PaymentInstruction == nil {
+		u.PaymentInstruction = &PaymentInstruction{}
+	}
+	u.PaymentInstruction.PlatformFees = append(u.PaymentInstruction.PlatformFees, PlatformFee{Amount: NewMoney(fee), Payee: payee})
+	return b
+}
+
+// Payer sets the payer of the order
+func (b *OrderBuilder) Payer(payer CreateOrderPayer) *OrderBuilder {
+	b.request.Payer = &payer
+	return b
+}
+
+// ApplicationContext sets the application context of the order,
+// e.g. its return and cancel URLs
+func (b *OrderBuilder) ApplicationContext(appContext ApplicationContext) *OrderBuilder {
+	b.request.ApplicationContext = &appContext
+	return b
+}
+
+// Build computes the amounts of the purchase units and validates them,
+// see ValidatePurchaseUnits. It returns a *ValidationError for invalid units
+func (b *OrderBuilder) Build() (*CreateOrderRequest, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	request := b.request
+	request.PurchaseUnits = make([]PurchaseUnitRequest, len(b.units))
+	for i, u := range b.units {
+		pu, err := u.build(b.currency)
+		if err != nil {
+			return nil, err
+		}
+		request.PurchaseUnits[i] = pu
+	}
+
+	if err := ValidatePurchaseUnits(request.PurchaseUnits); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Create builds the order and creates it with c
+func (b *OrderBuilder) Create(ctx context.Context, c *Client, opts ...RequestOption) (*Order, error) {
+	request, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateOrderContext(ctx, request.Intent, request.PurchaseUnits, request.Payer, request.ApplicationContext, opts...)
+}
+
+// unit returns the current purchase unit, to be changed
+func (b *OrderBuilder) unit() *unitBuilder {
+	u := b.units[len(b.units)-1]
+	u.touched = true
+	return u
+}
+
+// checkCurrency reports whether a is in the currency of the order, keeping the first error
+func (b *OrderBuilder) checkCurrency(a money.Amount) bool {
+	if b.err != nil {
+		return false
+	}
+	if a.Currency() != b.currency {
+		b.err = fmt.Errorf("%w: %s in a %s order", money.ErrCurrencyMismatch, a, b.currency)
+		return false
+	}
+	return true
+}
+
+// add returns total+a in the currency of the order, keeping the first error
+func (b *OrderBuilder) add(total, a money.Amount) money.Amount {
+	if !b.checkCurrency(a) {
+		return total
+	}
+	sum, err := total.Add(a)
+	if err != nil {
+		b.err = err
+	}
+	return sum
+}
+
+// build returns the purchase unit, with its breakdown when it has items or fees
+func (u *unitBuilder) build(currency string) (PurchaseUnitRequest, error) {
+	pu := u.PurchaseUnitRequest
+
+	// Items must be valid to be summed
+	v := &validator{}
+	var itemTotal money.Amount
+	tax := u.tax
+	taxed := false
+	for i := range u.Items {
+		price, itemTax, quantity, ok := u.Items[i].validate(v, fmt.Sprintf("%s/items/%d", purchaseUnitPath(u.ReferenceID), i), currency)
+		if !ok {
+			continue
+		}
+		taxed = taxed || u.Items[i].Tax != nil
+		line, err := price.Mul(quantity)
+		if err == nil {
+			itemTotal, err = itemTotal.Add(line)
+		}
+		if err == nil {
+			if line, err = itemTax.Mul(quantity); err == nil {
+				tax, err = tax.Add(line)
+			}
+		}
+		if err != nil {
+			return pu, err
+		}
+	}
+	if err := v.err(); err != nil {
+		return pu, err
+	}
+
+	parts := []money.Amount{tax, u.shipping, u.handling, u.insurance, u.shippingDiscount, u.discount}
+	hasBreakdown := len(u.Items) > 0
+	for _, p := range parts {
+		hasBreakdown = hasBreakdown || !p.IsZero()
+	}
+
+	if !hasBreakdown {
+		if u.amount == nil {
+			pu.Amount = &PurchaseUnitAmount{Currency: currency}
+		} else {
+			pu.Amount = NewPurchaseUnitAmount(*u.amount)
+		}
+		return pu, nil
+	}
+
+	total, err := money.Sum(itemTotal, tax, u.shipping, u.handling, u.insurance, u.shippingDiscount.Neg(), u.discount.Neg())
+	if err != nil {
+		return pu, err
+	}
+	if total.Currency() == "" {
+		total, _ = money.Zero(currency)
+	}
+
+	breakdown := &PurchaseUnitAmountBreakdown{
+		TaxTotal:         breakdownMoney(tax),
+		Shipping:         breakdownMoney(u.shipping),
+		Handling:         breakdownMoney(u.handling),
+		Insurance:        breakdownMoney(u.insurance),
+		ShippingDiscount: breakdownMoney(u.shippingDiscount),
+		Discount:         breakdownMoney(u.discount),
+	}
+	if len(u.Items) > 0 {
+		breakdown.ItemTotal = NewMoney(itemTotal)
+	}
+	if taxed && breakdown.TaxTotal == nil {
+		// PayPal requires a tax_total as soon as an item has a tax, even a zero one
+		zero, _ := money.Zero(currency)
+		breakdown.TaxTotal = NewMoney(zero)
+	}
+
+	pu.Amount = NewPurchaseUnitAmount(total)
+	if u.amount != nil {
+		// An explicit amount is checked against the breakdown by the validation
+		pu.Amount.Currency = u.amount.Currency()
+		pu.Amount.Value = u.amount.Value()
+	}
+	pu.Amount.Breakdown = breakdown
+	return pu, nil
+}
+
+// breakdownMoney returns a as a Money, or nil when a is zero
+func breakdownMoney(a money.Amount) *Money {
+	if a.IsZero() {
+		return nil
+	}
+	return NewMoney(a)
+}
@@ -0,0 +1,114 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/siriele/paypal/money"
+)
+
+func TestOrderBuilder(t *testing.T) {
+	usd := func(v string) money.Amount { return money.MustParse(v, "USD") }
+
+	request, err := NewOrderBuilder(IntentCapture, "USD").
+		PurchaseUnit("shirts").
+		AddItem("T-Shirt", 2, usd("7.50")).
+		AddItemDetails(Item{Name: "Hoodie", Quantity: "1", UnitAmount: NewMoney(usd("30")), Tax: NewMoney(usd("2.40")), SKU: "H-1"}).
+		Shipping(usd("4.99")).
+		Discount(usd("5")).
+		PlatformFee(usd("1.00"), nil).
+		ShipTo("John Doe", ShippingDetailAddressPortable{CountryCode: "US", PostalCode: "95131"}).
+		PurchaseUnit("gift").
+		Amount(usd("10")).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(request.PurchaseUnits) != 2 {
+		t.Fatalf("expected 2 purchase units, got %d", len(request.PurchaseUnits))
+	}
+	shirts := request.PurchaseUnits[0]
+	b := shirts.Amount.Breakdown
+	if shirts.Amount.Value != "47.39" || b.ItemTotal.Value != "45.00" || b.TaxTotal.Value != "2.40" || b.Discount.Value != "5.00" || b.Handling != nil {
+		t.Fatalf("unexpected amount %+v, breakdown %+v", shirts.Amount, b)
+	}
+	if shirts.PaymentInstruction.PlatformFees[0].Amount.Value != "1.00" || shirts.Shipping.Name.FullName != "John Doe" {
+		t.Fatalf("unexpected purchase unit %+v", shirts)
+	}
+	if gift := request.PurchaseUnits[1]; gift.ReferenceID != "gift" || gift.Amount.Value != "10.00" || gift.Amount.Breakdown != nil {
+		t.Fatalf("unexpected purchase unit %+v", gift)
+	}
+
+	// The shipping set before PurchaseUnit stays on a unit of its own
+	_, err = NewOrderBuilder(IntentCapture, "USD").
+		Shipping(usd("4.99")).
+		PurchaseUnit("second").
+		Amount(usd("6")).
+		Build()
+	if found := issues(t, err); found["/purchase_units/0/reference_id"] != IssueReferenceIDRequired {
+		t.Fatalf("expected the first unit to be kept, got %v", err)
+	}
+
+	request, err = NewOrderBuilder(IntentCapture, "USD").
+		AddItemDetails(Item{Name: "Book", Quantity: "2", UnitAmount: NewMoney(usd("12")), Tax: NewMoney(usd("0"))}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := request.PurchaseUnits[0].Amount.Breakdown; b.TaxTotal == nil || b.TaxTotal.Value != "0.00" {
+		t.Fatalf("expected a zero tax total for zero rated items, got %+v", b)
+	}
+
+	_, err = NewOrderBuilder(IntentCapture, "USD").
+		AddItemDetails(Item{Name: "Shirt", Quantity: "one", UnitAmount: NewMoney(usd("1"))}).
+		Build()
+	if found := issues(t, err); found["/purchase_units/@reference_id=='default'/items/0/quantity"] != IssueInvalidParameterSyntax {
+		t.Fatalf("expected invalid quantity, got %v", err)
+	}
+
+	_, err = NewOrderBuilder(IntentCapture, "USD").Shipping(money.MustParse("1", "EUR")).Build()
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("expected currency mismatch, got %v", err)
+	}
+
+	_, err = NewOrderBuilder(IntentCapture, "USD").PlatformFee(money.MustParse("1", "EUR"), nil).Build()
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("expected currency mismatch for the platform fee, got %v", err)
+	}
+
+	_, err = NewOrderBuilder(IntentCapture, "USD").Amount(money.MustParse("10", "EUR")).Build()
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("expected currency mismatch for the amount, got %v", err)
+	}
+}
+
+func TestOrderBuilderCreate(t *testing.T) {
+	var got CreateOrderRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"id":"5O190127TN364715T","status":"CREATED"}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	order, err := NewOrderBuilder(IntentAuthorize, "JPY").
+		AddItem("Ramen", 3, money.MustParse("800", "JPY")).
+		ApplicationContext(ApplicationContext{ReturnURL: "https://example.com/return"}).
+		Create(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.ID != "5O190127TN364715T" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+	if got.Intent != IntentAuthorize || got.PurchaseUnits[0].Amount.Value != "2400" || got.ApplicationContext.ReturnURL != "https://example.com/return" {
+		t.Fatalf("unexpected request %+v", got)
+	}
+}
@@ -0,0 +1,25 @@
+package paypal
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPaymentPatchHelpers(t *testing.T) {
+	patches := []PaymentPatch{
+		PatchAmount("", &PurchaseUnitAmount{Currency: "USD", Value: "5.00"}),
+		PatchInvoiceID("ref", "INV-1"),
+		PatchCustomID("ref", ""),
+	}
+
+	b, err := json.Marshal(patches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"op":"replace","path":"/purchase_units/@reference_id=='default'/amount","value":{"currency_code":"USD","value":"5.00"}},` +
+		`{"op":"add","path":"/purchase_units/@reference_id=='ref'/invoice_id","value":"INV-1"},` +
+		`{"op":"remove","path":"/purchase_units/@reference_id=='ref'/custom_id"}]`
+	if string(b) != expected {
+		t.Fatalf("expected %s, got %s", expected, b)
+	}
+}
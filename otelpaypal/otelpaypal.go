@@ -0,0 +1,217 @@
+// Package otelpaypal instruments a paypal.Client with OpenTelemetry traces and metrics.
+//
+// Every call becomes a client span named after its operation, e.g. paypal.CaptureOrder,
+// carrying the endpoint, the status, the PayPal debug ID, the IDs found in the path
+// (paypal.order_id, paypal.capture_id...) and the error name of failed calls:
+//
+//	inst, err := otelpaypal.Instrument(c)
+//	if err != nil {
+//		return err
+//	}
+//
+//	// Count retries, optional
+//	policy := paypal.DefaultRetryPolicy
+//	policy.OnAttempt = inst.OnAttempt
+//	c.SetRetryPolicy(&policy)
+//
+// The instrumentation records the metrics:
+//
+//   - paypal.client.requests, the number of calls by operation and status
+//   - paypal.client.duration, the duration of calls in seconds, retries included
+//   - paypal.client.retries, the number of retried attempts by operation
+//   - paypal.client.token_refreshes, the number of access token requests
+//   - paypal.client.errors, the number of failed calls by operation and error name
+package otelpaypal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/siriele/paypal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and the meter
+const ScopeName = "github.com/siriele/paypal/otelpaypal"
+
+// errTransport names the calls which failed without an answer from PayPal
+const errTransport = "TRANSPORT_ERROR"
+
+// Attribute keys of spans and metrics
+const (
+	keyOperation  = attribute.Key("paypal.operation")
+	keyDebugID    = attribute.Key("paypal.debug_id")
+	keyErrorName  = attribute.Key("paypal.error.name")
+	keyAttempt    = attribute.Key("paypal.attempt")
+	keyMethod     = attribute.Key("http.request.method")
+	keyRoute      = attribute.Key("http.route")
+	keyStatusCode = attribute.Key("http.response.status_code")
+)
+
+type (
+	// Option configures an Instrumentation
+	Option func(*config)
+
+	config struct {
+		tracerProvider trace.TracerProvider
+		meterProvider  metric.MeterProvider
+	}
+
+	// Instrumentation traces and measures the calls of paypal clients,
+	// see Instrument
+	Instrumentation struct {
+		tracer         trace.Tracer
+		requests       metric.Int64Counter
+		duration       metric.Float64Histogram
+		retries        metric.Int64Counter
+		tokenRefreshes metric.Int64Counter
+		errors         metric.Int64Counter
+	}
+)
+
+// WithTracerProvider sets the provider of the tracer, the global one by default
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the provider of the meter, the global one by default
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// New returns an Instrumentation, add its Middleware to clients with Use
+func New(opts ...Option) (*Instrumentation, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+	i := &Instrumentation{tracer: cfg.tracerProvider.Tracer(ScopeName)}
+
+	var err error
+	if i.requests, err = meter.Int64Counter("paypal.client.requests",
+		metric.WithDescription("Calls made to PayPal"), metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+	if i.duration, err = meter.Float64Histogram("paypal.client.duration",
+		metric.WithDescription("Duration of the calls made to PayPal, retries included"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if i.retries, err = meter.Int64Counter("paypal.client.retries",
+		metric.WithDescription("Attempts retried after a transient failure"), metric.WithUnit("{attempt}")); err != nil {
+		return nil, err
+	}
+	if i.tokenRefreshes, err = meter.Int64Counter("paypal.client.token_refreshes",
+		metric.WithDescription("Access token requests"), metric.WithUnit("{token}")); err != nil {
+		return nil, err
+	}
+	if i.errors, err = meter.Int64Counter("paypal.client.errors",
+		metric.WithDescription("Failed calls by error name"), metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Instrument adds a new Instrumentation to c and returns it
+func Instrument(c *paypal.Client, opts ...Option) (*Instrumentation, error) {
+	i, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.Use(i.Middleware)
+
+	return i, nil
+}
+
+// Middleware traces and measures the calls sent by next
+func (i *Instrumentation) Middleware(next paypal.Handler) paypal.Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		r := match(req)
+		attrs := []attribute.KeyValue{keyOperation.String(r.operation), keyMethod.String(req.Method)}
+		if r.path != "" {
+			attrs = append(attrs, keyRoute.String(r.path))
+		}
+
+		ctx, span := i.tracer.Start(req.Context(), "paypal."+r.operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+			trace.WithAttributes(r.params(req)...),
+		)
+		defer span.End()
+
+		start := time.Now()
+		resp, err := next(req.WithContext(ctx))
+		elapsed := time.Since(start)
+
+		if resp != nil {
+			attrs = append(attrs, keyStatusCode.Int(resp.StatusCode))
+			span.SetAttributes(keyStatusCode.Int(resp.StatusCode))
+			if debugID := resp.Header.Get(paypal.HeaderDebugID); debugID != "" {
+				span.SetAttributes(keyDebugID.String(debugID))
+			}
+		}
+		if err != nil {
+			name := errorName(err)
+			span.SetAttributes(keyErrorName.String(name))
+			span.RecordError(err)
+			span.SetStatus(codes.Error, name)
+			i.errors.Add(ctx, 1, metric.WithAttributes(append(attrs, keyErrorName.String(name))...))
+		}
+
+		i.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+		i.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(attrs...))
+		if r.operation == opGetAccessToken {
+			i.tokenRefreshes.Add(ctx, 1, metric.WithAttributes(attrs...))
+		}
+
+		return resp, err
+	}
+}
+
+// OnAttempt counts retries and adds them as events to the span of the call,
+// set it as the OnAttempt of the retry policy of the instrumented clients
+func (i *Instrumentation) OnAttempt(a paypal.RetryAttempt) {
+	if !a.Retry {
+		return
+	}
+
+	ctx := a.Request.Context()
+	op := keyOperation.String(match(a.Request).operation)
+	i.retries.Add(ctx, 1, metric.WithAttributes(op))
+
+	event := []attribute.KeyValue{keyAttempt.Int(a.Attempt), attribute.String("paypal.retry.delay", a.Delay.String())}
+	if a.Response != nil {
+		event = append(event, keyStatusCode.Int(a.Response.StatusCode))
+	}
+	trace.SpanFromContext(ctx).AddEvent("paypal.retry", trace.WithAttributes(event...))
+}
+
+// errorName returns the name of the PayPal error err, falling back on its status
+func errorName(err error) string {
+	var errResp *paypal.ErrorResponse
+	if !errors.As(err, &errResp) {
+		return errTransport
+	}
+	if errResp.Name != "" {
+		return errResp.Name
+	}
+	if errResp.Response != nil {
+		return fmt.Sprintf("HTTP_%d", errResp.Response.StatusCode)
+	}
+	return errTransport
+}
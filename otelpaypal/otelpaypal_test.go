@@ -0,0 +1,153 @@
+package otelpaypal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/siriele/paypal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrument(t *testing.T) {
+	var failures int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(paypal.HeaderDebugID, "debug-"+r.Method)
+		switch r.URL.Path {
+		case "/v1/oauth2/token":
+			w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+		case "/v2/checkout/orders/O-1":
+			if failures++; failures == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"id":"O-1","status":"APPROVED"}`))
+		case "/v2/checkout/orders/O-1/capture":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"name":"UNPROCESSABLE_ENTITY","details":[{"issue":"ORDER_NOT_APPROVED"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	c, _ := paypal.NewClient("foo", "bar", ts.URL)
+	inst, err := Instrument(c,
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := paypal.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, OnAttempt: inst.OnAttempt}
+	c.SetRetryPolicy(&policy)
+
+	if _, err := c.GetOrder("O-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CaptureOrder("O-1", paypal.CaptureOrderRequest{}); err == nil {
+		t.Fatal("expected the capture to fail")
+	}
+
+	ended := spans.Ended()
+	names := make([]string, len(ended))
+	for i, s := range ended {
+		names[i] = s.Name()
+	}
+	expected := []string{"paypal.GetAccessToken", "paypal.GetOrder", "paypal.CaptureOrder"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected spans %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected spans %v, got %v", expected, names)
+		}
+	}
+
+	order, capture := ended[1], ended[2]
+	assertAttrs(t, order.Attributes(), map[attribute.Key]attribute.Value{
+		"paypal.order_id":           attribute.StringValue("O-1"),
+		"http.route":                attribute.StringValue("/v2/checkout/orders/{order_id}"),
+		"http.response.status_code": attribute.IntValue(200),
+		"paypal.debug_id":           attribute.StringValue("debug-GET"),
+	})
+	if events := order.Events(); len(events) != 1 || events[0].Name != "paypal.retry" {
+		t.Errorf("expected a retry event, got %+v", events)
+	}
+	assertAttrs(t, capture.Attributes(), map[attribute.Key]attribute.Value{
+		"paypal.error.name":         attribute.StringValue("UNPROCESSABLE_ENTITY"),
+		"http.response.status_code": attribute.IntValue(422),
+	})
+	if capture.Status().Code != codes.Error {
+		t.Errorf("expected the capture span to fail, got %+v", capture.Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	sums := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range sum.DataPoints {
+					sums[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+	for name, value := range map[string]int64{
+		"paypal.client.requests":        3,
+		"paypal.client.retries":         1,
+		"paypal.client.token_refreshes": 1,
+		"paypal.client.errors":          1,
+	} {
+		if sums[name] != value {
+			t.Errorf("expected %s to be %d, got %d", name, value, sums[name])
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		method, path, operation string
+	}{
+		{"POST", "/v2/checkout/orders", "CreateOrder"},
+		{"PATCH", "/v2/checkout/orders/O-1", "UpdateOrder"},
+		{"POST", "/v1/customer/disputes/PP-D-1/make-offer", "MakeOffer"},
+		{"GET", "/v1/identity/openidconnect/userinfo/", "GetUserInfo"},
+		{"GET", "/v1/customer/partners/P/merchant-integrations/M", "ShowSellerStatus"},
+		{"GET", "/v1/unknown", "Send"},
+		{"PUT", "/v2/checkout/orders/O-1", "Send"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "https://api.paypal.com"+tt.path, nil)
+		if op := match(req).operation; op != tt.operation {
+			t.Errorf("expected %s %s to be %s, got %s", tt.method, tt.path, tt.operation, op)
+		}
+	}
+}
+
+func assertAttrs(t *testing.T, attrs []attribute.KeyValue, expected map[attribute.Key]attribute.Value) {
+	t.Helper()
+	got := map[attribute.Key]attribute.Value{}
+	for _, kv := range attrs {
+		got[kv.Key] = kv.Value
+	}
+	for k, v := range expected {
+		if got[k] != v {
+			t.Errorf("expected %s=%s, got %s", k, v.Emit(), got[k].Emit())
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package otelpaypal
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// opGetAccessToken is the operation of token requests
+	opGetAccessToken = "GetAccessToken"
+	// opSend names the calls matching no route, e.g. requests built by hand
+	opSend = "Send"
+)
+
+// route maps an endpoint to the name of the Client method calling it. The
+// segments {name} of path match any ID, recorded as the attribute paypal.name
+type route struct {
+	method    string
+	path      string
+	operation string
+}
+
+// routes are the endpoints called by paypal.Client
+var routes = []route{
+	{http.MethodPost, "/v1/oauth2/token", opGetAccessToken},
+
+	{http.MethodPost, "/v2/checkout/orders", "CreateOrder"},
+	{http.MethodGet, "/v2/checkout/orders/{order_id}", "GetOrder"},
+	{http.MethodPatch, "/v2/checkout/orders/{order_id}", "UpdateOrder"},
+	{http.MethodPost, "/v2/checkout/orders/{order_id}/authorize", "AuthorizeOrder"},
+	{http.MethodPost, "/v2/checkout/orders/{order_id}/capture", "CaptureOrder"},
+	{http.MethodPost, "/v2/checkout/orders/{order_id}/confirm-payment-source", "ConfirmPaymentSource"},
+	{http.MethodPost, "/v2/checkout/orders/{order_id}/track", "AddTracking"},
+
+	{http.MethodGet, "/v2/payments/authorizations/{authorization_id}", "GetAuthorization"},
+	{http.MethodPost, "/v2/payments/authorizations/{authorization_id}/capture", "CaptureAuthorization"},
+	{http.MethodPost, "/v2/payments/authorizations/{authorization_id}/void", "VoidAuthorization"},
+	{http.MethodPost, "/v2/payments/authorizations/{authorization_id}/reauthorize", "ReauthorizeAuthorization"},
+	{http.MethodGet, "/v2/payments/captures/{capture_id}", "GetCapture"},
+	{http.MethodPost, "/v2/payments/captures/{capture_id}/refund", "RefundCapture"},
+	{http.MethodGet, "/v2/payments/refunds/{refund_id}", "GetRefund"},
+	{http.MethodPost, "/v1/shipping/trackers-batch", "UpdateTracking"},
+
+	{http.MethodGet, "/v1/payments/sale/{sale_id}", "GetSale"},
+	{http.MethodPost, "/v1/payments/sale/{sale_id}/refund", "RefundSale"},
+	{http.MethodGet, "/v1/payments/refund/{refund_id}", "GetSaleRefund"},
+
+	{http.MethodPost, "/v1/payments/payouts", "CreateSinglePayout"},
+	{http.MethodGet, "/v1/payments/payouts/{payout_batch_id}", "GetPayout"},
+	{http.MethodGet, "/v1/payments/payouts-item/{payout_item_id}", "GetPayoutItem"},
+	{http.MethodPost, "/v1/payments/payouts-item/{payout_item_id}/cancel", "CancelPayoutItem"},
+
+	{http.MethodPost, "/v1/payments/billing-plans", "CreateBillingPlan"},
+	{http.MethodGet, "/v1/payments/billing-plans", "ListBillingPlans"},
+	{http.MethodGet, "/v1/payments/billing-plans/{plan_id}", "GetBillingPlan"},
+	{http.MethodPatch, "/v1/payments/billing-plans/{plan_id}", "UpdateBillingPlan"},
+	{http.MethodPost, "/v1/payments/billing-agreements", "CreateBillingAgreement"},
+	{http.MethodPost, "/v1/payments/billing-agreements/{token}/agreement-execute", "ExecuteApprovedAgreement"},
+	{http.MethodGet, "/v1/payments/billing-agreements/{agreement_id}", "GetBillingAgreement"},
+	{http.MethodPost, "/v1/payments/billing-agreements/{agreement_id}/suspend", "SuspendBillingAgreement"},
+	{http.MethodPost, "/v1/payments/billing-agreements/{agreement_id}/re-activate", "ReactivateBillingAgreement"},
+	{http.MethodPost, "/v1/payments/billing-agreements/{agreement_id}/cancel", "CancelBillingAgreement"},
+	{http.MethodPost, "/v1/payments/billing-agreements/{agreement_id}/set-balance", "SetBillingAgreementBalance"},
+	{http.MethodPost, "/v1/payments/billing-agreements/{agreement_id}/bill-balance", "BillBillingAgreementBalance"},
+	{http.MethodGet, "/v1/payments/billing-agreements/{agreement_id}/transactions", "ListBillingAgreementTransactions"},
+
+	{http.MethodPost, "/v1/catalogs/products", "CreateProduct"},
+	{http.MethodGet, "/v1/catalogs/products", "ListProducts"},
+	{http.MethodGet, "/v1/catalogs/products/{product_id}", "GetProduct"},
+	{http.MethodPatch, "/v1/catalogs/products/{product_id}", "UpdateProduct"},
+
+	{http.MethodPost, "/v1/billing/plans", "CreateSubscriptionPlan"},
+	{http.MethodGet, "/v1/billing/plans", "ListSubscriptionPlans"},
+	{http.MethodGet, "/v1/billing/plans/{plan_id}", "GetSubscriptionPlan"},
+	{http.MethodPatch, "/v1/billing/plans/{plan_id}", "UpdateSubscriptionPlan"},
+	{http.MethodPost, "/v1/billing/plans/{plan_id}/activate", "ActivateSubscriptionPlan"},
+	{http.MethodPost, "/v1/billing/plans/{plan_id}/deactivate", "DeactivateSubscriptionPlan"},
+	{http.MethodPost, "/v1/billing/plans/{plan_id}/update-pricing-schemes", "UpdateSubscriptionPlanPricing"},
+
+	{http.MethodPost, "/v1/billing/subscriptions", "CreateSubscription"},
+	{http.MethodGet, "/v1/billing/subscriptions/{subscription_id}", "GetSubscription"},
+	{http.MethodPost, "/v1/billing/subscriptions/{subscription_id}/revise", "ReviseSubscription"},
+	{http.MethodPost, "/v1/billing/subscriptions/{subscription_id}/suspend", "SuspendSubscription"},
+	{http.MethodPost, "/v1/billing/subscriptions/{subscription_id}/cancel", "CancelSubscription"},
+	{http.MethodPost, "/v1/billing/subscriptions/{subscription_id}/activate", "ActivateSubscription"},
+	{http.MethodPost, "/v1/billing/subscriptions/{subscription_id}/capture", "CaptureSubscription"},
+	{http.MethodGet, "/v1/billing/subscriptions/{subscription_id}/transactions", "ListSubscriptionTransactions"},
+
+	{http.MethodPost, "/v1/vault/credit-cards", "StoreCreditCard"},
+	{http.MethodGet, "/v1/vault/credit-cards", "GetCreditCards"},
+	{http.MethodGet, "/v1/vault/credit-cards/{credit_card_id}", "GetCreditCard"},
+	{http.MethodPatch, "/v1/vault/credit-cards/{credit_card_id}", "PatchCreditCard"},
+	{http.MethodDelete, "/v1/vault/credit-cards/{credit_card_id}", "DeleteCreditCard"},
+
+	{http.MethodGet, "/v1/customer/disputes", "ListDisputes"},
+	{http.MethodGet, "/v1/customer/disputes/{dispute_id}", "GetDispute"},
+	{http.MethodPost, "/v1/customer/disputes/{dispute_id}/accept-claim", "AcceptClaim"},
+	{http.MethodPost, "/v1/customer/disputes/{dispute_id}/provide-evidence", "ProvideEvidence"},
+	{http.MethodPost, "/v1/customer/disputes/{dispute_id}/appeal", "AppealDispute"},
+	{http.MethodPost, "/v1/customer/disputes/{dispute_id}/send-message", "SendDisputeMessage"},
+	{http.MethodPost, "/v1/customer/disputes/{dispute_id}/make-offer", "MakeOffer"},
+	{http.MethodPost, "/v1/customer/disputes/{dispute_id}/escalate", "EscalateDispute"},
+
+	{http.MethodPost, "/v2/customer/partner-referrals", "CreatePartnerReferral"},
+	{http.MethodGet, "/v2/customer/partner-referrals/{referral_id}", "GetPartnerReferral"},
+	{http.MethodGet, "/v1/customer/partners/{partner_id}/merchant-integrations/{merchant_id}", "ShowSellerStatus"},
+
+	{http.MethodPost, "/v1/identity/openidconnect/tokenservice", "GrantNewAccessToken"},
+	{http.MethodGet, "/v1/identity/openidconnect/userinfo", "GetUserInfo"},
+
+	{http.MethodPost, "/v1/payment-experience/web-profiles", "CreateWebProfile"},
+	{http.MethodGet, "/v1/payment-experience/web-profiles", "GetWebProfiles"},
+	{http.MethodGet, "/v1/payment-experience/web-profiles/{profile_id}", "GetWebProfile"},
+	{http.MethodPut, "/v1/payment-experience/web-profiles/{profile_id}", "SetWebProfile"},
+	{http.MethodDelete, "/v1/payment-experience/web-profiles/{profile_id}", "DeleteWebProfile"},
+
+	{http.MethodPost, "/v1/notifications/webhooks", "CreateWebhook"},
+	{http.MethodGet, "/v1/notifications/webhooks", "ListWebhooks"},
+	{http.MethodGet, "/v1/notifications/webhooks/{webhook_id}", "GetWebhook"},
+	{http.MethodPatch, "/v1/notifications/webhooks/{webhook_id}", "UpdateWebhook"},
+	{http.MethodDelete, "/v1/notifications/webhooks/{webhook_id}", "DeleteWebhook"},
+	{http.MethodGet, "/v1/notifications/webhooks-event-types", "ListWebhookEventTypes"},
+	{http.MethodGet, "/v1/notifications/webhooks-events", "ListWebhookEvents"},
+	{http.MethodGet, "/v1/notifications/webhooks-events/{event_id}", "GetWebhookEvent"},
+	{http.MethodPost, "/v1/notifications/webhooks-events/{event_id}/resend", "ResendWebhookEvent"},
+	{http.MethodPost, "/v1/notifications/simulate-event", "SimulateWebhookEvent"},
+	{http.MethodPost, "/v1/notifications/verify-webhook-signature", "VerifyWebhookSignature"},
+}
+
+// match returns the route of req, a route without path named opSend when none matches
+func match(req *http.Request) route {
+	segments := splitPath(req.URL.Path)
+	for _, r := range routes {
+		if r.method == req.Method && r.matches(segments) {
+			return r
+		}
+	}
+	return route{method: req.Method, operation: opSend}
+}
+
+// matches reports whether the segments of a request path match r
+func (r route) matches(segments []string) bool {
+	pattern := splitPath(r.path)
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if !isParam(p) && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// params returns the IDs found in the path of req as attributes
+func (r route) params(req *http.Request) []attribute.KeyValue {
+	segments := splitPath(req.URL.Path)
+	var attrs []attribute.KeyValue
+	for i, p := range splitPath(r.path) {
+		if isParam(p) && i < len(segments) {
+			attrs = append(attrs, attribute.String("paypal."+p[1:len(p)-1], segments[i]))
+		}
+	}
+	return attrs
+}
+
+// splitPath returns the segments of path, ignoring its leading and trailing slashes
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// isParam reports whether the segment of a route path is an ID
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
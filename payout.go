@@ -1,19 +1,26 @@
 package paypal
 
 import (
+	"context"
 	"fmt"
 )
 
 // CreateSinglePayout submits a payout with an asynchronous API call, which immediately returns the results of a PayPal payment.
 // For email payout set RecipientType: "EMAIL" and receiver email into Receiver
 // Endpoint: POST /v1/payments/payouts
-func (c *Client) CreateSinglePayout(p Payout) (*PayoutResponse, error) {
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/payouts"), p)
+func (c *Client) CreateSinglePayout(p Payout, opts ...RequestOption) (*PayoutResponse, error) {
+	return c.CreateSinglePayoutContext(context.Background(), p, opts...)
+}
+
+// CreateSinglePayoutContext is like CreateSinglePayout but uses ctx for the request
+func (c *Client) CreateSinglePayoutContext(ctx context.Context, p Payout, opts ...RequestOption) (*PayoutResponse, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/payouts"), p)
 	response := &PayoutResponse{}
 
 	if err != nil {
 		return response, err
 	}
+	c.applyIdempotency(req, opts)
 
 	if err = c.SendWithAuth(req, response); err != nil {
 		return response, err
@@ -26,7 +33,12 @@ func (c *Client) CreateSinglePayout(p Payout) (*PayoutResponse, error) {
 // Also, returns IDs for the individual payout items. You can use these item IDs in other calls.
 // Endpoint: GET /v1/payments/payouts/ID
 func (c *Client) GetPayout(payoutBatchID string) (*PayoutResponse, error) {
-	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/payouts/"+payoutBatchID), nil)
+	return c.GetPayoutContext(context.Background(), payoutBatchID)
+}
+
+// GetPayoutContext is like GetPayout but uses ctx for the request
+func (c *Client) GetPayoutContext(ctx context.Context, payoutBatchID string) (*PayoutResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/payouts/"+payoutBatchID), nil)
 	response := &PayoutResponse{}
 
 	if err != nil {
@@ -44,7 +56,12 @@ func (c *Client) GetPayout(payoutBatchID string) (*PayoutResponse, error) {
 // Use this call to review the current status of a previously unclaimed, or pending, payout item.
 // Endpoint: GET /v1/payments/payouts-item/ID
 func (c *Client) GetPayoutItem(payoutItemID string) (*PayoutItemResponse, error) {
-	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/payouts-item/"+payoutItemID), nil)
+	return c.GetPayoutItemContext(context.Background(), payoutItemID)
+}
+
+// GetPayoutItemContext is like GetPayoutItem but uses ctx for the request
+func (c *Client) GetPayoutItemContext(ctx context.Context, payoutItemID string) (*PayoutItemResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/payouts-item/"+payoutItemID), nil)
 	response := &PayoutItemResponse{}
 
 	if err != nil {
@@ -62,7 +79,12 @@ func (c *Client) GetPayoutItem(payoutItemID string) (*PayoutItemResponse, error)
 // the funds are automatically returned to the sender. Use this call to cancel the unclaimed item before the automatic 30-day refund.
 // Endpoint: POST /v1/payments/payouts-item/ID/cancel
 func (c *Client) CancelPayoutItem(payoutItemID string) (*PayoutItemResponse, error) {
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/payouts-item/"+payoutItemID+"/cancel"), nil)
+	return c.CancelPayoutItemContext(context.Background(), payoutItemID)
+}
+
+// CancelPayoutItemContext is like CancelPayoutItem but uses ctx for the request
+func (c *Client) CancelPayoutItemContext(ctx context.Context, payoutItemID string) (*PayoutItemResponse, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/payouts-item/"+payoutItemID+"/cancel"), nil)
 	response := &PayoutItemResponse{}
 
 	if err != nil {
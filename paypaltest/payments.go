@@ -0,0 +1,752 @@
+package paypaltest
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/siriele/paypal"
+)
+
+// AuthorizationLifetime is how long an authorization can be captured
+const AuthorizationLifetime = 29 * 24 * time.Hour
+
+type (
+	// order is an order with the IDs of the payments of its purchase units
+	order struct {
+		paypal.Order
+		payments []unitPayments
+	}
+
+	// unitPayments are the IDs of the payments of a purchase unit
+	unitPayments struct {
+		authorizations []string
+		captures       []string
+		refunds        []string
+	}
+
+	authorization struct {
+		paypal.Authorization
+		orderID  string
+		unit     int
+		captured *big.Rat
+	}
+
+	capture struct {
+		paypal.Capture
+		orderID  string
+		unit     int
+		refunded *big.Rat
+	}
+)
+
+// ApproveOrder approves order id, as the buyer does when redirected to the approve link
+func (s *Server) ApproveOrder(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[id]
+	if !ok {
+		return fmt.Errorf("paypaltest: order %s not found", id)
+	}
+	if o.Status != paypal.OrderStatusCreated {
+		return fmt.Errorf("paypaltest: order %s is %s, only CREATED orders can be approved", id, o.Status)
+	}
+	o.Status = paypal.OrderStatusApproved
+	o.UpdateTime = s.now()
+
+	return nil
+}
+
+// ExpireAuthorization expires authorization id, as PayPal does once AuthorizationLifetime is over
+func (s *Server) ExpireAuthorization(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.authorizations[id]
+	if !ok {
+		return fmt.Errorf("paypaltest: authorization %s not found", id)
+	}
+	if a.Status != paypal.AuthorizationStatusCreated && a.Status != paypal.AuthorizationStatusPartiallyCaptured {
+		return fmt.Errorf("paypaltest: authorization %s is %s and cannot expire", id, a.Status)
+	}
+	a.Status = paypal.AuthorizationStatusExpired
+	a.UpdateTime = s.now()
+
+	return nil
+}
+
+// serveOrders serves /v2/checkout/orders
+func (s *Server) serveOrders(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		s.createOrder(w, r)
+	case len(rest) == 0:
+		methodNotAllowed(w)
+	case s.orders[rest[0]] == nil:
+		notFound(w)
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.orderView(s.orders[rest[0]]))
+	case len(rest) == 1 && r.Method == http.MethodPatch:
+		s.updateOrder(w, r, s.orders[rest[0]])
+	case len(rest) == 2 && rest[1] == "authorize" && r.Method == http.MethodPost:
+		s.authorizeOrder(w, r, s.orders[rest[0]])
+	case len(rest) == 2 && rest[1] == "capture" && r.Method == http.MethodPost:
+		s.captureOrder(w, r, s.orders[rest[0]])
+	case len(rest) == 2 && rest[1] == "confirm-payment-source" && r.Method == http.MethodPost:
+		s.confirmPaymentSource(w, r, s.orders[rest[0]])
+	case len(rest) == 2 && rest[1] == "track" && r.Method == http.MethodPost:
+		s.addTracking(w, r, s.orders[rest[0]])
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) createOrder(w http.ResponseWriter, r *http.Request) {
+	request := struct {
+		Intent        paypal.PaymentIntent         `json:"intent"`
+		PurchaseUnits []paypal.PurchaseUnitRequest `json:"purchase_units"`
+	}{}
+	if !decode(w, r, &request) {
+		return
+	}
+
+	var details []paypal.ErrorResponseDetail
+	switch request.Intent {
+	case paypal.IntentCapture, paypal.IntentAuthorize:
+	case "":
+		details = append(details, paypal.ErrorResponseDetail{Field: "/intent", Location: "body", Issue: "MISSING_REQUIRED_PARAMETER"})
+	default:
+		details = append(details, paypal.ErrorResponseDetail{Field: "/intent", Value: string(request.Intent), Location: "body", Issue: "INVALID_PARAMETER_VALUE"})
+	}
+	if len(request.PurchaseUnits) == 0 {
+		details = append(details, paypal.ErrorResponseDetail{Field: "/purchase_units", Location: "body", Issue: "MISSING_REQUIRED_PARAMETER"})
+	}
+
+	references := map[string]bool{}
+	for i, pu := range request.PurchaseUnits {
+		if pu.ReferenceID == "" {
+			if len(request.PurchaseUnits) > 1 {
+				details = append(details, paypal.ErrorResponseDetail{Field: fmt.Sprintf("/purchase_units/%d/reference_id", i), Location: "body", Issue: "REFERENCE_ID_REQUIRED"})
+			}
+			pu.ReferenceID = "default"
+		}
+		if references[pu.ReferenceID] {
+			details = append(details, paypal.ErrorResponseDetail{Field: fmt.Sprintf("/purchase_units/%d/reference_id", i), Value: pu.ReferenceID, Location: "body", Issue: "DUPLICATE_REFERENCE_ID"})
+		}
+		references[pu.ReferenceID] = true
+
+		field := fmt.Sprintf("/purchase_units/@reference_id=='%s'/amount", pu.ReferenceID)
+		switch {
+		case pu.Amount == nil:
+			details = append(details, paypal.ErrorResponseDetail{Field: field, Location: "body", Issue: "MISSING_REQUIRED_PARAMETER"})
+		case pu.Amount.Currency == "":
+			details = append(details, paypal.ErrorResponseDetail{Field: field + "/currency_code", Location: "body", Issue: "MISSING_REQUIRED_PARAMETER"})
+		default:
+			if v, ok := parseValue(pu.Amount.Value); !ok || v.Sign() <= 0 {
+				details = append(details, paypal.ErrorResponseDetail{Field: field + "/value", Value: pu.Amount.Value, Location: "body", Issue: "INVALID_PARAMETER_SYNTAX"})
+			}
+		}
+		request.PurchaseUnits[i] = pu
+	}
+
+	if len(details) > 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Request is not well-formed, syntactically incorrect, or violates schema.", details...)
+		return
+	}
+
+	id := s.id("ORDER", 17)
+	o := &order{
+		Order: paypal.Order{
+			ID:         id,
+			Status:     paypal.OrderStatusCreated,
+			Intent:     request.Intent,
+			CreateTime: s.now(),
+			UpdateTime: s.now(),
+		},
+		payments: make([]unitPayments, len(request.PurchaseUnits)),
+	}
+	for _, pu := range request.PurchaseUnits {
+		o.PurchaseUnits = append(o.PurchaseUnits, paypal.PurchaseUnit{
+			ReferenceID:    pu.ReferenceID,
+			Amount:         pu.Amount,
+			Payee:          pu.Payee,
+			Description:    pu.Description,
+			CustomID:       pu.CustomID,
+			InvoiceID:      pu.InvoiceID,
+			SoftDescriptor: pu.SoftDescriptor,
+			Items:          pu.Items,
+			Shipping:       pu.Shipping,
+		})
+	}
+	action := "capture"
+	if o.Intent == paypal.IntentAuthorize {
+		action = "authorize"
+	}
+	o.Links = []paypal.Link{
+		s.link("self", http.MethodGet, "/v2/checkout/orders/"+id),
+		{Href: "https://www.sandbox.paypal.com/checkoutnow?token=" + id, Rel: "approve", Method: http.MethodGet},
+		s.link("update", http.MethodPatch, "/v2/checkout/orders/"+id),
+		s.link(action, http.MethodPost, "/v2/checkout/orders/"+id+"/"+action),
+	}
+	s.orders[id] = o
+
+	writeJSON(w, http.StatusCreated, s.orderView(o))
+}
+
+func (s *Server) updateOrder(w http.ResponseWriter, r *http.Request, o *order) {
+	if o.Status != paypal.OrderStatusCreated && o.Status != paypal.OrderStatusApproved {
+		unprocessable(w, "ORDER_ALREADY_COMPLETED", "The order cannot be patched after it is completed.")
+		return
+	}
+
+	var patches []paypal.PaymentPatch
+	if !decode(w, r, &patches) {
+		return
+	}
+
+	updated := o.Order
+	updated.PurchaseUnits = append([]paypal.PurchaseUnit(nil), o.PurchaseUnits...)
+	for i, p := range patches {
+		if err := patchOrder(&updated, p); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Request is not well-formed, syntactically incorrect, or violates schema.",
+				paypal.ErrorResponseDetail{Field: fmt.Sprintf("/%d/path", i), Value: p.Path, Location: "body", Issue: "INVALID_PATCH_PATH", Description: err.Error()})
+			return
+		}
+	}
+
+	o.Order = updated
+	o.UpdateTime = s.now()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// patchOrder applies p to o, paths are either /intent or start with
+// /purchase_units/@reference_id=='ID'
+func patchOrder(o *paypal.Order, p paypal.PaymentPatch) error {
+	if p.Path == "/intent" {
+		intent, _ := p.Value.(string)
+		if p.Operation != "replace" || (intent != string(paypal.IntentCapture) && intent != string(paypal.IntentAuthorize)) {
+			return fmt.Errorf("invalid intent patch")
+		}
+		o.Intent = paypal.PaymentIntent(intent)
+		return nil
+	}
+
+	const prefix = "/purchase_units/@reference_id=='"
+	if !strings.HasPrefix(p.Path, prefix) {
+		return fmt.Errorf("path %s cannot be patched", p.Path)
+	}
+	end := strings.Index(p.Path[len(prefix):], "'")
+	if end < 0 {
+		return fmt.Errorf("invalid purchase unit selector")
+	}
+	ref, path := p.Path[len(prefix):len(prefix)+end], p.Path[len(prefix)+end+1:]
+
+	for i := range o.PurchaseUnits {
+		if o.PurchaseUnits[i].ReferenceID == ref {
+			return patchJSON(&o.PurchaseUnits[i], p.Operation, path, p.Value)
+		}
+	}
+	return fmt.Errorf("purchase unit %s not found", ref)
+}
+
+func (s *Server) authorizeOrder(w http.ResponseWriter, r *http.Request, o *order) {
+	request := paypal.AuthorizeOrderRequest{}
+	if !decode(w, r, &request) {
+		return
+	}
+	if !s.checkout(w, o, paypal.IntentAuthorize, request.PaymentSource != nil) {
+		return
+	}
+
+	for i, pu := range o.PurchaseUnits {
+		id := s.id("AUTH", 17)
+		a := &authorization{
+			Authorization: paypal.Authorization{
+				ID:             id,
+				CustomID:       pu.CustomID,
+				InvoiceID:      pu.InvoiceID,
+				Status:         paypal.AuthorizationStatusCreated,
+				Amount:         &paypal.PurchaseUnitAmount{Currency: pu.Amount.Currency, Value: pu.Amount.Value},
+				CreateTime:     s.now(),
+				UpdateTime:     s.now(),
+				ExpirationTime: paypal.PTime{Time: s.now().Add(AuthorizationLifetime)},
+				Links:          s.authorizationLinks(id),
+			},
+			orderID:  o.ID,
+			unit:     i,
+			captured: new(big.Rat),
+		}
+		s.authorizations[id] = a
+		o.payments[i].authorizations = append(o.payments[i].authorizations, id)
+	}
+	o.Status = paypal.OrderStatusCompleted
+	o.UpdateTime = s.now()
+
+	view := s.orderView(o)
+	writeJSON(w, http.StatusCreated, paypal.AuthorizeOrderResponse{
+		CreateTime:    view.CreateTime,
+		UpdateTime:    view.UpdateTime,
+		ID:            view.ID,
+		Status:        view.Status,
+		Intent:        view.Intent,
+		PurchaseUnits: view.PurchaseUnits,
+	})
+}
+
+func (s *Server) captureOrder(w http.ResponseWriter, r *http.Request, o *order) {
+	request := paypal.CaptureOrderRequest{}
+	if !decode(w, r, &request) {
+		return
+	}
+	if !s.checkout(w, o, paypal.IntentCapture, request.PaymentSource != nil) {
+		return
+	}
+
+	for i, pu := range o.PurchaseUnits {
+		c := s.newCapture(o.ID, i, pu.CustomID, pu.InvoiceID, &paypal.Amount{Currency: pu.Amount.Currency, Value: pu.Amount.Value}, true)
+		o.payments[i].captures = append(o.payments[i].captures, c.ID)
+	}
+	o.Status = paypal.OrderStatusCompleted
+	o.UpdateTime = s.now()
+
+	view := s.orderView(o)
+	writeJSON(w, http.StatusCreated, paypal.CaptureOrderResponse{
+		ID:            view.ID,
+		Status:        view.Status,
+		PurchaseUnits: view.PurchaseUnits,
+	})
+}
+
+// confirmPaymentSource approves o, as if the payer confirmed paying with the payment source.
+// Card numbers are kept masked
+func (s *Server) confirmPaymentSource(w http.ResponseWriter, r *http.Request, o *order) {
+	request := paypal.ConfirmPaymentSourceRequest{}
+	if !decode(w, r, &request) {
+		return
+	}
+	if request.PaymentSource == nil || (request.PaymentSource.Card == nil && request.PaymentSource.Token == nil) {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Request is not well-formed, syntactically incorrect, or violates schema.",
+			paypal.ErrorResponseDetail{Field: "/payment_source", Location: "body", Issue: "MISSING_REQUIRED_PARAMETER"})
+		return
+	}
+	if o.Status != paypal.OrderStatusCreated && o.Status != paypal.OrderStatusApproved {
+		unprocessable(w, "ORDER_ALREADY_COMPLETED", "The order cannot be confirmed after it is completed.")
+		return
+	}
+
+	source := &paypal.PaymentSource{Token: request.PaymentSource.Token}
+	if card := request.PaymentSource.Card; card != nil {
+		source.Card = &paypal.PaymentSourceCard{Name: card.Name, LastDigits: card.LastDigits, CardType: card.CardType}
+		if len(card.Number) >= 4 {
+			source.Card.LastDigits = card.Number[len(card.Number)-4:]
+		}
+	}
+	o.PaymentSource = source
+	o.Status = paypal.OrderStatusApproved
+	o.UpdateTime = s.now()
+
+	writeJSON(w, http.StatusOK, s.orderView(o))
+}
+
+// addTracking adds a shipped tracker to the purchase unit of a capture of o
+func (s *Server) addTracking(w http.ResponseWriter, r *http.Request, o *order) {
+	request := paypal.OrderTrackerRequest{}
+	if !decode(w, r, &request) {
+		return
+	}
+
+	var details []paypal.ErrorResponseDetail
+	required := []struct{ field, value string }{
+		{"/capture_id", request.CaptureID},
+		{"/tracking_number", request.TrackingNumber},
+		{"/carrier", request.Carrier},
+	}
+	for _, f := range required {
+		if f.value == "" {
+			details = append(details, paypal.ErrorResponseDetail{Field: f.field, Location: "body", Issue: "MISSING_REQUIRED_PARAMETER"})
+		}
+	}
+	if len(details) > 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Request is not well-formed, syntactically incorrect, or violates schema.", details...)
+		return
+	}
+	c := s.captures[request.CaptureID]
+	if c == nil || c.orderID != o.ID {
+		unprocessable(w, "CAPTURE_ID_NOT_FOUND", "Specified capture ID does not exist. Check the capture ID and try again.")
+		return
+	}
+
+	pu := &o.PurchaseUnits[c.unit]
+	shipping := paypal.ShippingDetail{}
+	if pu.Shipping != nil {
+		shipping = *pu.Shipping
+	}
+	shipping.Trackers = append(append([]paypal.OrderTracker(nil), shipping.Trackers...), paypal.OrderTracker{
+		ID:         c.ID + "-" + request.TrackingNumber,
+		Status:     paypal.TrackingStatusShipped,
+		Items:      request.Items,
+		CreateTime: s.now(),
+		UpdateTime: s.now(),
+	})
+	pu.Shipping = &shipping
+	o.UpdateTime = s.now()
+
+	writeJSON(w, http.StatusCreated, s.orderView(o))
+}
+
+// checkout checks o can be authorized or captured with intent,
+// an order paid with a payment source does not need to be approved
+func (s *Server) checkout(w http.ResponseWriter, o *order, intent paypal.PaymentIntent, paymentSource bool) bool {
+	switch {
+	case o.Intent != intent:
+		unprocessable(w, "ACTION_DOES_NOT_MATCH_INTENT", fmt.Sprintf("Order was created with an intent to '%s'. Please use /v2/checkout/orders/%s/%s instead.", o.Intent, o.ID, strings.ToLower(string(o.Intent))))
+	case o.Status == paypal.OrderStatusCompleted && intent == paypal.IntentCapture:
+		unprocessable(w, "ORDER_ALREADY_CAPTURED", "Order already captured. If 'intent=CAPTURE' only one capture per order is allowed.")
+	case o.Status == paypal.OrderStatusCompleted:
+		unprocessable(w, "ORDER_ALREADY_AUTHORIZED", "Order already authorized. If 'intent=AUTHORIZE' only one authorization per order is allowed.")
+	case o.Status == paypal.OrderStatusVoided:
+		unprocessable(w, "ORDER_ALREADY_VOIDED", "The order has been voided.")
+	case o.Status != paypal.OrderStatusApproved && !paymentSource:
+		unprocessable(w, "ORDER_NOT_APPROVED", "Payer has not yet approved the Order for payment. Please redirect the payer to the 'rel':'approve' url returned as part of the HATEOAS links within the Create Order call or provide a valid payment_source in the request.")
+	default:
+		return true
+	}
+	return false
+}
+
+// orderView returns o with the payments of its purchase units
+func (s *Server) orderView(o *order) paypal.Order {
+	view := o.Order
+	view.PurchaseUnits = make([]paypal.PurchaseUnit, len(o.PurchaseUnits))
+	for i, pu := range o.PurchaseUnits {
+		p := o.payments[i]
+		if len(p.authorizations)+len(p.captures) > 0 {
+			pu.Payments = &paypal.PurchaseUnitPayments{}
+			for _, id := range p.authorizations {
+				pu.Payments.Authorizations = append(pu.Payments.Authorizations, s.authorizations[id].Authorization)
+			}
+			for _, id := range p.captures {
+				pu.Payments.Captures = append(pu.Payments.Captures, s.captures[id].Capture)
+			}
+			for _, id := range p.refunds {
+				refund := s.refunds[id]
+				pu.Payments.Refunds = append(pu.Payments.Refunds, paypal.Refund{
+					ID:          refund.ID,
+					Amount:      refund.Amount,
+					InvoiceID:   refund.InvoiceID,
+					CreateTime:  refund.CreateTime,
+					UpdateTime:  refund.UpdateTime,
+					Status:      refund.Status,
+					NoteToPayer: refund.NoteToPayer,
+				})
+			}
+		}
+		view.PurchaseUnits[i] = pu
+	}
+
+	return view
+}
+
+// serveAuthorizations serves /v2/payments/authorizations
+func (s *Server) serveAuthorizations(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 || s.authorizations[rest[0]] == nil {
+		notFound(w)
+		return
+	}
+	a := s.authorizations[rest[0]]
+
+	switch {
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, a.Authorization)
+	case len(rest) == 2 && rest[1] == "capture" && r.Method == http.MethodPost:
+		s.captureAuthorization(w, r, a)
+	case len(rest) == 2 && rest[1] == "void" && r.Method == http.MethodPost:
+		if s.capturable(w, a) {
+			a.Status = paypal.AuthorizationStatusVoided
+			a.UpdateTime = s.now()
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case len(rest) == 2 && rest[1] == "reauthorize" && r.Method == http.MethodPost:
+		s.reauthorize(w, r, a)
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) captureAuthorization(w http.ResponseWriter, r *http.Request, a *authorization) {
+	request := paypal.PaymentCaptureRequest{}
+	if !decode(w, r, &request) || !s.capturable(w, a) {
+		return
+	}
+
+	total, _ := parseValue(a.Amount.Value)
+	remaining := new(big.Rat).Sub(total, a.captured)
+	amount := &paypal.Money{Currency: a.Amount.Currency, Value: formatValue(remaining, a.Amount.Value)}
+	if request.Amount != nil {
+		amount = request.Amount
+	}
+
+	value, ok := parseValue(amount.Value)
+	switch {
+	case !ok || value.Sign() <= 0:
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Request is not well-formed, syntactically incorrect, or violates schema.",
+			paypal.ErrorResponseDetail{Field: "/amount/value", Value: amount.Value, Location: "body", Issue: "INVALID_PARAMETER_SYNTAX"})
+		return
+	case amount.Currency != a.Amount.Currency:
+		unprocessable(w, "CURRENCY_MISMATCH", "Currency of capture must be the same as currency of authorization.")
+		return
+	case value.Cmp(remaining) > 0:
+		unprocessable(w, "MAX_CAPTURE_AMOUNT_EXCEEDED", "Capture amount exceeds allowable limit.")
+		return
+	}
+
+	a.captured.Add(a.captured, value)
+	a.Status = paypal.AuthorizationStatusPartiallyCaptured
+	if request.FinalCapture || a.captured.Cmp(total) == 0 {
+		a.Status = paypal.AuthorizationStatusCaptured
+	}
+	a.UpdateTime = s.now()
+
+	c := s.newCapture(a.orderID, a.unit, a.CustomID, firstNonEmpty(request.InvoiceID, a.InvoiceID), &paypal.Amount{Currency: amount.Currency, Value: amount.Value}, a.Status == paypal.AuthorizationStatusCaptured)
+	if o, ok := s.orders[a.orderID]; ok {
+		o.payments[a.unit].captures = append(o.payments[a.unit].captures, c.ID)
+	}
+
+	writeJSON(w, http.StatusCreated, paypal.PaymentCaptureResponse{
+		ID:           c.ID,
+		Status:       c.Status,
+		Amount:       &paypal.Money{Currency: c.Amount.Currency, Value: c.Amount.Value},
+		InvoiceID:    c.InvoiceID,
+		CustomID:     a.CustomID,
+		FinalCapture: c.IsFinalCapture,
+		CreateTime:   c.CreateTime,
+		UpdateTime:   c.UpdateTime,
+	})
+}
+
+func (s *Server) reauthorize(w http.ResponseWriter, r *http.Request, a *authorization) {
+	request := struct {
+		Amount *paypal.Amount `json:"amount"`
+	}{}
+	if !decode(w, r, &request) {
+		return
+	}
+	if a.Status != paypal.AuthorizationStatusCreated {
+		unprocessable(w, "REAUTHORIZATION_NOT_ALLOWED", fmt.Sprintf("An authorization in the %s state cannot be reauthorized.", a.Status))
+		return
+	}
+
+	amount := &paypal.PurchaseUnitAmount{Currency: a.Amount.Currency, Value: a.Amount.Value}
+	if request.Amount != nil {
+		if value, ok := parseValue(request.Amount.Value); !ok || value.Sign() <= 0 || request.Amount.Currency != a.Amount.Currency {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Request is not well-formed, syntactically incorrect, or violates schema.",
+				paypal.ErrorResponseDetail{Field: "/amount", Location: "body", Issue: "INVALID_PARAMETER_VALUE"})
+			return
+		}
+		amount = &paypal.PurchaseUnitAmount{Currency: request.Amount.Currency, Value: request.Amount.Value}
+	}
+
+	id := s.id("AUTH", 17)
+	reauthorized := &authorization{
+		Authorization: paypal.Authorization{
+			ID:             id,
+			CustomID:       a.CustomID,
+			InvoiceID:      a.InvoiceID,
+			Status:         paypal.AuthorizationStatusCreated,
+			Amount:         amount,
+			CreateTime:     s.now(),
+			UpdateTime:     s.now(),
+			ExpirationTime: paypal.PTime{Time: s.now().Add(AuthorizationLifetime)},
+			Links:          s.authorizationLinks(id),
+		},
+		orderID:  a.orderID,
+		unit:     a.unit,
+		captured: new(big.Rat),
+	}
+	s.authorizations[id] = reauthorized
+	if o, ok := s.orders[a.orderID]; ok {
+		o.payments[a.unit].authorizations = append(o.payments[a.unit].authorizations, id)
+	}
+
+	writeJSON(w, http.StatusCreated, reauthorized.Authorization)
+}
+
+// capturable checks a can still be captured or voided
+func (s *Server) capturable(w http.ResponseWriter, a *authorization) bool {
+	switch a.Status {
+	case paypal.AuthorizationStatusCreated, paypal.AuthorizationStatusPartiallyCaptured:
+		return true
+	case paypal.AuthorizationStatusCaptured:
+		unprocessable(w, "AUTHORIZATION_ALREADY_CAPTURED", "Authorization has been previously captured and hence cannot be voided or captured again.")
+	case paypal.AuthorizationStatusVoided:
+		unprocessable(w, "AUTHORIZATION_VOIDED", "A voided authorization cannot be captured or reauthorized.")
+	case paypal.AuthorizationStatusExpired:
+		unprocessable(w, "AUTHORIZATION_EXPIRED", "An expired authorization cannot be captured.")
+	default:
+		unprocessable(w, "AUTHORIZATION_NOT_CAPTURABLE", fmt.Sprintf("An authorization in the %s state cannot be captured.", a.Status))
+	}
+	return false
+}
+
+func (s *Server) authorizationLinks(id string) []paypal.Link {
+	return []paypal.Link{
+		s.link("self", http.MethodGet, "/v2/payments/authorizations/"+id),
+		s.link("capture", http.MethodPost, "/v2/payments/authorizations/"+id+"/capture"),
+		s.link("void", http.MethodPost, "/v2/payments/authorizations/"+id+"/void"),
+		s.link("reauthorize", http.MethodPost, "/v2/payments/authorizations/"+id+"/reauthorize"),
+	}
+}
+
+// newCapture stores and returns a completed capture of amount
+func (s *Server) newCapture(orderID string, unit int, customID, invoiceID string, amount *paypal.Amount, final bool) *capture {
+	id := s.id("CAPTURE", 17)
+	c := &capture{
+		Capture: paypal.Capture{
+			ID:             id,
+			Status:         paypal.CaptureStatusCompleted,
+			Amount:         amount,
+			IsFinalCapture: final,
+			InvoiceID:      invoiceID,
+			CustomID:       customID,
+			CreateTime:     s.now(),
+			UpdateTime:     s.now(),
+			Links: []paypal.Link{
+				s.link("self", http.MethodGet, "/v2/payments/captures/"+id),
+				s.link("refund", http.MethodPost, "/v2/payments/captures/"+id+"/refund"),
+			},
+		},
+		orderID:  orderID,
+		unit:     unit,
+		refunded: new(big.Rat),
+	}
+	s.captures[id] = c
+
+	return c
+}
+
+// serveCaptures serves /v2/payments/captures
+func (s *Server) serveCaptures(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 || s.captures[rest[0]] == nil {
+		notFound(w)
+		return
+	}
+	c := s.captures[rest[0]]
+
+	switch {
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, c.Capture)
+	case len(rest) == 2 && rest[1] == "refund" && r.Method == http.MethodPost:
+		s.refundCapture(w, r, c)
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) refundCapture(w http.ResponseWriter, r *http.Request, c *capture) {
+	request := paypal.RefundRequest{}
+	if !decode(w, r, &request) {
+		return
+	}
+
+	switch c.Status {
+	case paypal.CaptureStatusCompleted, paypal.CaptureStatusPartiallyRefunded:
+	case paypal.CaptureStatusRefunded:
+		unprocessable(w, "CAPTURE_FULLY_REFUNDED", "The capture has already been fully refunded.")
+		return
+	default:
+		unprocessable(w, "CAPTURE_STATUS_NOT_VALID", fmt.Sprintf("A capture in the %s state cannot be refunded.", c.Status))
+		return
+	}
+
+	total, _ := parseValue(c.Amount.Value)
+	remaining := new(big.Rat).Sub(total, c.refunded)
+	amount := &paypal.Amount{Currency: c.Amount.Currency, Value: formatValue(remaining, c.Amount.Value)}
+	if request.Amount != nil {
+		amount = &paypal.Amount{Currency: request.Amount.Currency, Value: request.Amount.Value}
+	}
+
+	value, ok := parseValue(amount.Value)
+	switch {
+	case !ok || value.Sign() <= 0:
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Request is not well-formed, syntactically incorrect, or violates schema.",
+			paypal.ErrorResponseDetail{Field: "/amount/value", Value: amount.Value, Location: "body", Issue: "INVALID_PARAMETER_SYNTAX"})
+		return
+	case amount.Currency != c.Amount.Currency:
+		unprocessable(w, "REFUND_CURRENCY_MISMATCH", "Refund must be in the same currency as the capture.")
+		return
+	case value.Cmp(remaining) > 0:
+		unprocessable(w, "REFUND_AMOUNT_EXCEEDED", "The refund amount must be less than or equal to the capture amount that has not yet been refunded.")
+		return
+	}
+
+	c.refunded.Add(c.refunded, value)
+	c.Status = paypal.CaptureStatusPartiallyRefunded
+	if c.refunded.Cmp(total) == 0 {
+		c.Status = paypal.CaptureStatusRefunded
+	}
+	c.UpdateTime = s.now()
+
+	id := s.id("REFUND", 17)
+	refund := &paypal.RefundResponse{
+		ID:          id,
+		InvoiceID:   firstNonEmpty(request.InvoiceID, c.InvoiceID),
+		Amount:      amount,
+		Status:      paypal.RefundStatusCompleted,
+		NoteToPayer: request.NoteToPayer,
+		CreateTime:  s.now(),
+		UpdateTime:  s.now(),
+		Links: []paypal.Link{
+			s.link("self", http.MethodGet, "/v2/payments/refunds/"+id),
+			s.link("up", http.MethodGet, "/v2/payments/captures/"+c.ID),
+		},
+	}
+	s.refunds[id] = refund
+	if o, ok := s.orders[c.orderID]; ok {
+		o.payments[c.unit].refunds = append(o.payments[c.unit].refunds, id)
+	}
+
+	writeJSON(w, http.StatusCreated, refund)
+}
+
+// serveRefunds serves /v2/payments/refunds
+func (s *Server) serveRefunds(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) != 1 || s.refunds[rest[0]] == nil {
+		notFound(w)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.refunds[rest[0]])
+}
+
+// parseValue parses a decimal amount, e.g. "10.99"
+func parseValue(v string) (*big.Rat, bool) {
+	if v == "" || strings.ContainsAny(v, "eE/") {
+		return nil, false
+	}
+	return new(big.Rat).SetString(v)
+}
+
+// formatValue formats r with as many decimals as like
+func formatValue(r *big.Rat, like string) string {
+	decimals := 0
+	if i := strings.IndexByte(like, '.'); i >= 0 {
+		decimals = len(like) - i - 1
+	}
+	return r.FloatString(decimals)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
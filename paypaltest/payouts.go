@@ -0,0 +1,171 @@
+package paypaltest
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/siriele/paypal"
+)
+
+// Payout item statuses without a paypal.TransactionStatus constant
+const (
+	payoutItemSuccess  = "SUCCESS"
+	payoutItemReturned = "RETURNED"
+)
+
+// ClaimPayoutItem makes the receiver of unclaimed payout item id claim it.
+// Payout items are UNCLAIMED until then, as for receivers without a PayPal account
+func (s *Server) ClaimPayoutItem(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.payoutItems[id]
+	if !ok {
+		return fmt.Errorf("paypaltest: payout item %s not found", id)
+	}
+	if item.TransactionStatus != string(paypal.TransactionStatusUnclaimed) {
+		return fmt.Errorf("paypaltest: payout item %s is %s, only UNCLAIMED items can be claimed", id, item.TransactionStatus)
+	}
+	item.TransactionStatus = payoutItemSuccess
+
+	return nil
+}
+
+// servePayouts serves /v1/payments/payouts
+func (s *Server) servePayouts(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		s.createPayout(w, r)
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		batch, ok := s.payouts[rest[0]]
+		if !ok {
+			notFound(w)
+			return
+		}
+		response := *batch
+		response.Items = nil
+		for _, item := range batch.Items {
+			response.Items = append(response.Items, *s.payoutItems[item.PayoutItemID])
+		}
+		writeJSON(w, http.StatusOK, response)
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) createPayout(w http.ResponseWriter, r *http.Request) {
+	p := paypal.Payout{}
+	if !decode(w, r, &p) {
+		return
+	}
+
+	var details []paypal.ErrorResponseDetail
+	if p.SenderBatchHeader == nil {
+		details = append(details, paypal.ErrorResponseDetail{Field: "sender_batch_header", Issue: "Required field is missing"})
+	} else if p.SenderBatchHeader.SenderBatchID != "" {
+		for _, batch := range s.payouts {
+			if batch.BatchHeader.SenderBatchHeader.SenderBatchID == p.SenderBatchHeader.SenderBatchID {
+				writeError(w, http.StatusBadRequest, "USER_BUSINESS_ERROR", "User business error.",
+					paypal.ErrorResponseDetail{Field: "SENDER_BATCH_ID", Issue: "Batch with given sender_batch_id already exists"})
+				return
+			}
+		}
+	}
+	if len(p.Items) == 0 {
+		details = append(details, paypal.ErrorResponseDetail{Field: "items", Issue: "Required field is missing"})
+	}
+
+	total := new(big.Rat)
+	currency, like := "", "0.00"
+	for i, item := range p.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if item.Receiver == "" {
+			details = append(details, paypal.ErrorResponseDetail{Field: field + ".receiver", Issue: "Required field is missing"})
+		}
+		if item.Amount == nil {
+			details = append(details, paypal.ErrorResponseDetail{Field: field + ".amount", Issue: "Required field is missing"})
+			continue
+		}
+		value, ok := parseValue(item.Amount.Value)
+		if !ok || value.Sign() <= 0 {
+			details = append(details, paypal.ErrorResponseDetail{Field: field + ".amount.value", Issue: "Invalid amount"})
+			continue
+		}
+		if currency == "" {
+			currency, like = item.Amount.Currency, item.Amount.Value
+		} else if item.Amount.Currency != currency {
+			details = append(details, paypal.ErrorResponseDetail{Field: field + ".amount.currency", Issue: "All items must use the same currency"})
+		}
+		total.Add(total, value)
+	}
+
+	if len(details) > 0 {
+		writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request - see details.", details...)
+		return
+	}
+
+	batchID := s.id("BATCH", 13)
+	batch := &paypal.PayoutResponse{
+		BatchHeader: &paypal.BatchHeader{
+			Amount:            &paypal.AmountPayout{Currency: currency, Value: formatValue(total, like)},
+			Fees:              &paypal.AmountPayout{Currency: currency, Value: formatValue(new(big.Rat), like)},
+			PayoutBatchID:     batchID,
+			BatchStatus:       payoutItemSuccess,
+			TimeCreated:       s.now(),
+			TimeCompleted:     s.now(),
+			SenderBatchHeader: p.SenderBatchHeader,
+		},
+		Links: []paypal.Link{s.link("self", http.MethodGet, "/v1/payments/payouts/"+batchID)},
+	}
+	for i := range p.Items {
+		item := p.Items[i]
+		id := s.id("ITEM", 13)
+		processed := s.now().Time
+		s.payoutItems[id] = &paypal.PayoutItemResponse{
+			PayoutItemID:      id,
+			TransactionID:     s.id("TX", 17),
+			TransactionStatus: string(paypal.TransactionStatusUnclaimed),
+			PayoutBatchID:     batchID,
+			PayoutItemFee:     &paypal.AmountPayout{Currency: currency, Value: formatValue(new(big.Rat), like)},
+			PayoutItem:        &item,
+			TimeProcessed:     &processed,
+			Links:             []paypal.Link{s.link("item", http.MethodGet, "/v1/payments/payouts-item/"+id)},
+		}
+		batch.Items = append(batch.Items, paypal.PayoutItemResponse{PayoutItemID: id})
+	}
+	s.payouts[batchID] = batch
+
+	// Batches are processed asynchronously, the creation only acknowledges them
+	writeJSON(w, http.StatusCreated, paypal.PayoutResponse{
+		BatchHeader: &paypal.BatchHeader{
+			PayoutBatchID:     batchID,
+			BatchStatus:       string(paypal.TransactionStatusPending),
+			SenderBatchHeader: p.SenderBatchHeader,
+		},
+		Links: batch.Links,
+	})
+}
+
+// servePayoutItems serves /v1/payments/payouts-item
+func (s *Server) servePayoutItems(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 || s.payoutItems[rest[0]] == nil {
+		notFound(w)
+		return
+	}
+	item := s.payoutItems[rest[0]]
+
+	switch {
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, item)
+	case len(rest) == 2 && rest[1] == "cancel" && r.Method == http.MethodPost:
+		if item.TransactionStatus != string(paypal.TransactionStatusUnclaimed) {
+			writeError(w, http.StatusBadRequest, "ITEM_CANCELLATION_FAILED", fmt.Sprintf("Only UNCLAIMED items can be cancelled, this one is %s.", item.TransactionStatus))
+			return
+		}
+		item.TransactionStatus = payoutItemReturned
+		writeJSON(w, http.StatusOK, item)
+	default:
+		notFound(w)
+	}
+}
@@ -0,0 +1,487 @@
+// Package paypaltest provides an in-process fake of the PayPal REST API,
+// so code using the paypal client can be tested offline and deterministically.
+//
+//	s := paypaltest.NewServer()
+//	defer s.Close()
+//
+//	c := s.NewClient()
+//	order, err := c.CreateOrder(paypal.IntentCapture, units, nil, nil)
+//	s.ApproveOrder(order.ID) // what the buyer does on paypal.com
+//	capture, err := c.CaptureOrder(order.ID, paypal.CaptureOrderRequest{})
+//
+// The fake keeps orders, authorizations, captures, refunds, payouts,
+// vaulted cards and web experience profiles in memory, enforces the state
+// transitions of the real API and answers with the same error shapes.
+// Fault injects errors to exercise failure paths
+package paypaltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/siriele/paypal"
+)
+
+// Credentials accepted by a Server unless changed
+const (
+	ClientID = "paypaltest-client-id"
+	Secret   = "paypaltest-secret"
+)
+
+// TokenLifetime is the lifetime of the access tokens issued by a Server
+const TokenLifetime = 9 * time.Hour
+
+type (
+	// Server is a fake PayPal REST API listening on a local address
+	Server struct {
+		*httptest.Server
+
+		// ClientID and Secret are the credentials accepted by the oauth2 endpoint
+		ClientID string
+		Secret   string
+
+		// Now returns the time stamped on resources, time.Now by default
+		Now func() time.Time
+
+		mu             sync.Mutex
+		seq            int
+		tokens         map[string]time.Time
+		orders         map[string]*order
+		authorizations map[string]*authorization
+		captures       map[string]*capture
+		refunds        map[string]*paypal.RefundResponse
+		payouts        map[string]*paypal.PayoutResponse
+		payoutItems    map[string]*paypal.PayoutItemResponse
+		cards          map[string]*paypal.CreditCard
+		cardIDs        []string
+		profiles       map[string]*paypal.WebProfile
+		profileIDs     []string
+		replays        map[string]*recorder
+		faults         []*Fault
+		requests       []*http.Request
+	}
+
+	// Fault makes the requests it matches fail with a PayPal error
+	Fault struct {
+		// Method and Path select the requests to fail, empty matches any.
+		// Path matches as a prefix, e.g. "/v2/checkout/orders"
+		Method string
+		Path   string
+
+		// Status is the HTTP status code answered, 500 by default
+		Status int
+		// Error is the body answered. Name and Message default to the ones
+		// PayPal uses for Status
+		Error paypal.ErrorResponse
+
+		// Times is how many requests fail before the fault is removed,
+		// 0 means once and a negative value means until Reset
+		Times int
+	}
+
+	// recorder keeps a response to replay it for a repeated PayPal-Request-Id
+	recorder struct {
+		status int
+		header http.Header
+		body   []byte
+	}
+)
+
+// NewServer starts and returns a new Server. Close it when done
+func NewServer() *Server {
+	s := &Server{
+		ClientID: ClientID,
+		Secret:   Secret,
+		Now:      time.Now,
+	}
+	s.Reset()
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// NewClient returns a paypal client using s and its credentials
+func (s *Server) NewClient() *paypal.Client {
+	c, err := paypal.NewClient(s.ClientID, s.Secret, s.URL)
+	if err != nil {
+		panic(err)
+	}
+	c.SetHTTPClient(s.Client())
+
+	return c
+}
+
+// Reset forgets every resource, token, fault and recorded request
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens = map[string]time.Time{}
+	s.orders = map[string]*order{}
+	s.authorizations = map[string]*authorization{}
+	s.captures = map[string]*capture{}
+	s.refunds = map[string]*paypal.RefundResponse{}
+	s.payouts = map[string]*paypal.PayoutResponse{}
+	s.payoutItems = map[string]*paypal.PayoutItemResponse{}
+	s.cards = map[string]*paypal.CreditCard{}
+	s.cardIDs = nil
+	s.profiles = map[string]*paypal.WebProfile{}
+	s.profileIDs = nil
+	s.replays = map[string]*recorder{}
+	s.faults = nil
+	s.requests = nil
+}
+
+// Inject adds f, faults are matched in the order they were injected
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Status == 0 {
+		f.Status = http.StatusInternalServerError
+	}
+	s.faults = append(s.faults, &f)
+}
+
+// ExpireTokens makes every access token issued so far invalid,
+// as if they had outlived TokenLifetime
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens = map[string]time.Time{}
+}
+
+// Requests returns the requests received so far, oldest first.
+// Their bodies have been consumed
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// Header implements http.ResponseWriter
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+// Write implements http.ResponseWriter
+func (rec *recorder) Write(b []byte) (int, error) {
+	rec.body = append(rec.body, b...)
+	return len(b), nil
+}
+
+// WriteHeader implements http.ResponseWriter
+func (rec *recorder) WriteHeader(status int) {
+	rec.status = status
+}
+
+// writeTo writes the recorded response to w
+func (rec *recorder) writeTo(w http.ResponseWriter) {
+	for k, v := range rec.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.status)
+	w.Write(rec.body)
+}
+
+// ServeHTTP serves the fake API
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r)
+
+	if f := s.fault(r); f != nil {
+		writeJSON(w, f.Status, f.Error)
+		return
+	}
+
+	if r.URL.Path == "/v1/oauth2/token" {
+		s.token(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{
+			"error":             "invalid_token",
+			"error_description": "Token signature verification failed",
+		})
+		return
+	}
+
+	// A request repeating a PayPal-Request-Id gets the response of the first one
+	key := ""
+	if id := r.Header.Get(paypal.HeaderRequestID); id != "" && r.Method == http.MethodPost {
+		key = r.URL.Path + " " + id
+		if rec, ok := s.replays[key]; ok {
+			rec.writeTo(w)
+			return
+		}
+	}
+
+	rec := &recorder{header: http.Header{}, status: http.StatusOK}
+	s.route(rec, r)
+
+	if key != "" && rec.status < 500 {
+		s.replays[key] = rec
+	}
+	rec.writeTo(w)
+}
+
+// route dispatches r to the handler of its endpoint
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 {
+		notFound(w)
+		return
+	}
+	resource, rest := strings.Join(parts[:3], "/"), parts[3:]
+
+	switch resource {
+	case "v2/checkout/orders":
+		s.serveOrders(w, r, rest)
+	case "v2/payments/authorizations":
+		s.serveAuthorizations(w, r, rest)
+	case "v2/payments/captures":
+		s.serveCaptures(w, r, rest)
+	case "v2/payments/refunds":
+		s.serveRefunds(w, r, rest)
+	case "v1/payments/payouts":
+		s.servePayouts(w, r, rest)
+	case "v1/payments/payouts-item":
+		s.servePayoutItems(w, r, rest)
+	case "v1/vault/credit-cards":
+		s.serveCards(w, r, rest)
+	case "v1/payment-experience/web-profiles":
+		s.serveProfiles(w, r, rest)
+	default:
+		notFound(w)
+	}
+}
+
+// fault returns the fault matching r, if any
+func (s *Server) fault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+
+		if f.Times == 0 || f.Times == 1 {
+			s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+		} else if f.Times > 1 {
+			f.Times--
+		}
+
+		res := *f
+		if res.Error.Name == "" {
+			res.Error.Name = errorName(f.Status)
+		}
+		if res.Error.Message == "" {
+			res.Error.Message = http.StatusText(f.Status)
+		}
+		if res.Error.DebugID == "" {
+			res.Error.DebugID = s.id("", 13)
+		}
+		return &res
+	}
+
+	return nil
+}
+
+// token serves POST /v1/oauth2/token
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		notFound(w)
+		return
+	}
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != s.ClientID || secret != s.Secret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{
+			"error":             "invalid_client",
+			"error_description": "Client Authentication failed",
+		})
+		return
+	}
+
+	token := "A21AA" + s.id("", 20)
+	s.tokens[token] = s.Now().Add(TokenLifetime)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"scope":        "https://uri.paypal.com/services/payments/payment",
+		"access_token": token,
+		"token_type":   "Bearer",
+		"app_id":       "APP-80W284485P519543T",
+		"expires_in":   int64(TokenLifetime / time.Second),
+		"nonce":        s.Now().UTC().Format(time.RFC3339) + s.id("", 8),
+	})
+}
+
+// authorized reports whether r carries a valid access token
+func (s *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	expiresAt, ok := s.tokens[token]
+	return ok && s.Now().Before(expiresAt)
+}
+
+// id returns a new upper case identifier of n characters starting with prefix
+func (s *Server) id(prefix string, n int) string {
+	s.seq++
+	return fmt.Sprintf("%s%0*d", prefix, n-len(prefix), s.seq)
+}
+
+// now returns the current time as stamped on resources
+func (s *Server) now() paypal.PTime {
+	return paypal.PTime{Time: s.Now().UTC().Truncate(time.Second)}
+}
+
+// link returns a link to path on s
+func (s *Server) link(rel, method, path string) paypal.Link {
+	return paypal.Link{Href: s.URL + path, Rel: rel, Method: method}
+}
+
+// decode decodes the JSON body of r into v, answering 400 when it is malformed
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	var buf bytes.Buffer
+	buf.ReadFrom(r.Body)
+	if buf.Len() == 0 {
+		return true
+	}
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Request is not well-formed, syntactically incorrect, or violates schema.",
+			paypal.ErrorResponseDetail{Field: "/", Issue: "MALFORMED_REQUEST_JSON"})
+		return false
+	}
+	return true
+}
+
+// patchJSON applies a JSON patch operation to the struct v points to.
+// path is a JSON pointer to a field, e.g. /billing_address/line1
+func patchJSON(v interface{}, op, path string, value interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	doc := map[string]interface{}{}
+	if err = json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	if path == "" || path == "/" {
+		return fmt.Errorf("the whole resource cannot be patched")
+	}
+	fields := strings.Split(strings.TrimPrefix(path, "/"), "/")
+
+	parent := doc
+	for i, f := range fields {
+		if i == len(fields)-1 {
+			switch op {
+			case "add", "replace":
+				parent[f] = value
+			case "remove":
+				delete(parent, f)
+			default:
+				return fmt.Errorf("unsupported op %q", op)
+			}
+			break
+		}
+		child, ok := parent[f].(map[string]interface{})
+		if !ok {
+			if op == "remove" {
+				return fmt.Errorf("path %s does not exist", path)
+			}
+			child = map[string]interface{}{}
+			parent[f] = child
+		}
+		parent = child
+	}
+
+	if b, err = json.Marshal(doc); err != nil {
+		return err
+	}
+	// Decode into a zero value, so pointers shared with the original are left untouched
+	rv := reflect.ValueOf(v).Elem()
+	fresh := reflect.New(rv.Type())
+	if err = json.Unmarshal(b, fresh.Interface()); err != nil {
+		return err
+	}
+	rv.Set(fresh.Elem())
+
+	return nil
+}
+
+// writeJSON answers status with v encoded as JSON
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if v == nil {
+		w.WriteHeader(status)
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+// writeError answers a PayPal error
+func writeError(w http.ResponseWriter, status int, name, message string, details ...paypal.ErrorResponseDetail) {
+	writeJSON(w, status, paypal.ErrorResponse{
+		Name:    name,
+		Message: message,
+		DebugID: "f" + strings.Repeat("0", 12),
+		Details: details,
+	})
+}
+
+// notFound answers 404 for an unknown resource
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "The specified resource does not exist.",
+		paypal.ErrorResponseDetail{Issue: "INVALID_RESOURCE_ID", Description: "Specified resource ID does not exist. Please check the resource ID and try again."})
+}
+
+// unprocessable answers 422 with issue, as the v2 API does for invalid state transitions
+func unprocessable(w http.ResponseWriter, issue, description string) {
+	writeError(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "The requested action could not be performed, semantically incorrect, or failed business validation.",
+		paypal.ErrorResponseDetail{Issue: issue, Description: description})
+}
+
+// methodNotAllowed answers 405
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_SUPPORTED", "The server does not implement the requested HTTP method.")
+}
+
+// errorName returns the name PayPal uses in errors answered with status
+func errorName(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "INVALID_REQUEST"
+	case http.StatusUnauthorized:
+		return "AUTHENTICATION_FAILURE"
+	case http.StatusForbidden:
+		return "NOT_AUTHORIZED"
+	case http.StatusNotFound:
+		return "RESOURCE_NOT_FOUND"
+	case http.StatusConflict:
+		return "RESOURCE_CONFLICT"
+	case http.StatusUnprocessableEntity:
+		return "UNPROCESSABLE_ENTITY"
+	case http.StatusTooManyRequests:
+		return "RATE_LIMIT_REACHED"
+	case http.StatusServiceUnavailable:
+		return "SERVICE_UNAVAILABLE"
+	default:
+		return "INTERNAL_SERVER_ERROR"
+	}
+}
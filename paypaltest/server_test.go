@@ -0,0 +1,342 @@
+package paypaltest_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/siriele/paypal"
+	"github.com/siriele/paypal/paypaltest"
+)
+
+var units = []paypal.PurchaseUnitRequest{{
+	ReferenceID: "ref-1",
+	Amount:      &paypal.PurchaseUnitAmount{Currency: "USD", Value: "10.00"},
+}}
+
+// issue returns the issue of the first detail of a PayPal error
+func issue(t *testing.T, err error) string {
+	t.Helper()
+	errResp, ok := err.(*paypal.ErrorResponse)
+	if !ok {
+		t.Fatalf("expected a PayPal error, got %v", err)
+	}
+	if len(errResp.Details) == 0 {
+		return errResp.Name
+	}
+	return errResp.Details[0].Issue
+}
+
+func TestCaptureAndRefund(t *testing.T) {
+	s := paypaltest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	order, err := c.CreateOrder(paypal.IntentCapture, units, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != paypal.OrderStatusCreated || len(order.Links) == 0 {
+		t.Fatalf("unexpected order %+v", order)
+	}
+
+	if _, err = c.CaptureOrder(order.ID, paypal.CaptureOrderRequest{}); issue(t, err) != "ORDER_NOT_APPROVED" {
+		t.Fatalf("expected unapproved order not to be captured, got %v", err)
+	}
+	if err = s.ApproveOrder(order.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	captured, err := c.CaptureOrder(order.ID, paypal.CaptureOrderRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if captured.Status != paypal.OrderStatusCompleted {
+		t.Fatalf("expected order to be completed, got %s", captured.Status)
+	}
+	captureID := captured.PurchaseUnits[0].Payments.Captures[0].ID
+
+	if _, err = c.CaptureOrder(order.ID, paypal.CaptureOrderRequest{}); issue(t, err) != "ORDER_ALREADY_CAPTURED" {
+		t.Fatalf("expected order to be captured once, got %v", err)
+	}
+
+	refund, err := c.RefundCapture(captureID, &paypal.RefundRequest{Amount: &paypal.Amount{Currency: "USD", Value: "4.00"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund.Status != paypal.RefundStatusCompleted || refund.Amount.Value != "4.00" {
+		t.Fatalf("unexpected refund %+v", refund)
+	}
+	if _, err = c.RefundCapture(captureID, &paypal.RefundRequest{Amount: &paypal.Amount{Currency: "USD", Value: "7.00"}}); issue(t, err) != "REFUND_AMOUNT_EXCEEDED" {
+		t.Fatalf("expected refund to exceed the capture, got %v", err)
+	}
+
+	got, err := c.GetRefund(refund.ID)
+	if err != nil || got.ID != refund.ID {
+		t.Fatalf("unexpected refund %+v, %v", got, err)
+	}
+
+	capture, err := c.GetCapture(captureID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capture.Status != paypal.CaptureStatusPartiallyRefunded || capture.Amount.Value != "10.00" {
+		t.Fatalf("unexpected capture %+v", capture)
+	}
+
+	order, err = c.GetOrder(order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payments := order.PurchaseUnits[0].Payments
+	if payments.Captures[0].Status != paypal.CaptureStatusPartiallyRefunded || len(payments.Refunds) != 1 {
+		t.Fatalf("unexpected payments %+v", payments)
+	}
+}
+
+func TestConfirmPatchAndTrack(t *testing.T) {
+	s := paypaltest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	order, err := c.CreateOrder(paypal.IntentCapture, units, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.UpdateOrder(order.ID, []paypal.PaymentPatch{
+		paypal.PatchAmount("ref-1", &paypal.PurchaseUnitAmount{Currency: "USD", Value: "12.00"}),
+		paypal.PatchShippingAddress("ref-1", &paypal.ShippingDetailAddressPortable{CountryCode: "US", PostalCode: "95131"}),
+		paypal.PatchInvoiceID("ref-1", "INV-1"),
+		paypal.PatchCustomID("ref-1", "cart-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.UpdateOrder(order.ID, []paypal.PaymentPatch{paypal.PatchCustomID("ref-1", "")}); err != nil {
+		t.Fatal(err)
+	}
+
+	card := &paypal.PaymentSourceCard{Name: "John Doe", Number: "4111111111111111", Expiry: "2030-01", SecurityCode: "123"}
+	confirmed, err := c.ConfirmPaymentSource(order.ID, paypal.ConfirmPaymentSourceRequest{PaymentSource: &paypal.PaymentSource{Card: card}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.Status != paypal.OrderStatusApproved || confirmed.PaymentSource.Card.LastDigits != "1111" || confirmed.PaymentSource.Card.Number != "" {
+		t.Fatalf("unexpected order %+v", confirmed)
+	}
+	pu := confirmed.PurchaseUnits[0]
+	if pu.Amount.Value != "12.00" || pu.Shipping.Address.PostalCode != "95131" || pu.InvoiceID != "INV-1" || pu.CustomID != "" {
+		t.Fatalf("unexpected purchase unit %+v", pu)
+	}
+
+	captured, err := c.CaptureOrder(order.ID, paypal.CaptureOrderRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	captureID := captured.PurchaseUnits[0].Payments.Captures[0].ID
+
+	if _, err = c.AddTracking(order.ID, paypal.OrderTrackerRequest{CaptureID: "unknown", TrackingNumber: "1Z", Carrier: "UPS"}); issue(t, err) != "CAPTURE_ID_NOT_FOUND" {
+		t.Fatalf("expected unknown capture to be rejected, got %v", err)
+	}
+	tracked, err := c.AddTracking(order.ID, paypal.OrderTrackerRequest{CaptureID: captureID, TrackingNumber: "1Z", Carrier: "UPS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trackers := tracked.PurchaseUnits[0].Shipping.Trackers
+	if len(trackers) != 1 || trackers[0].ID != captureID+"-1Z" || trackers[0].Status != paypal.TrackingStatusShipped {
+		t.Fatalf("unexpected trackers %+v", trackers)
+	}
+}
+
+func TestAuthorizeAndCapture(t *testing.T) {
+	s := paypaltest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	order, err := c.CreateOrder(paypal.IntentAuthorize, units, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.ApproveOrder(order.ID)
+
+	authorized, err := c.AuthorizeOrder(order.ID, paypal.AuthorizeOrderRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authID := authorized.PurchaseUnits[0].Payments.Authorizations[0].ID
+
+	capture, err := c.CaptureAuthorization(authID, &paypal.PaymentCaptureRequest{Amount: &paypal.Money{Currency: "USD", Value: "4.00"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capture.Status != paypal.CaptureStatusCompleted || capture.Amount.Value != "4.00" {
+		t.Fatalf("unexpected capture %+v", capture)
+	}
+	if _, err = c.CaptureAuthorization(authID, &paypal.PaymentCaptureRequest{Amount: &paypal.Money{Currency: "USD", Value: "6.01"}}); issue(t, err) != "MAX_CAPTURE_AMOUNT_EXCEEDED" {
+		t.Fatalf("expected capture to exceed the authorization, got %v", err)
+	}
+
+	auth, err := c.GetAuthorization(authID)
+	if err != nil || auth.Status != paypal.AuthorizationStatusPartiallyCaptured {
+		t.Fatalf("unexpected authorization %+v, %v", auth, err)
+	}
+
+	if err = c.VoidAuthorization(authID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.CaptureAuthorization(authID, &paypal.PaymentCaptureRequest{}); issue(t, err) != "AUTHORIZATION_VOIDED" {
+		t.Fatalf("expected voided authorization not to be captured, got %v", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	s := paypaltest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+	c.SetRetryPolicy(&paypal.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	s.Inject(paypaltest.Fault{Method: http.MethodPost, Path: "/v2/checkout/orders", Status: http.StatusServiceUnavailable})
+	order, err := c.CreateOrder(paypal.IntentCapture, units, nil, nil, paypal.WithRequestID("order-1"))
+	if err != nil {
+		t.Fatalf("expected the request to be retried, got %v", err)
+	}
+
+	again, err := c.CreateOrder(paypal.IntentCapture, units, nil, nil, paypal.WithRequestID("order-1"))
+	if err != nil || again.ID != order.ID {
+		t.Fatalf("expected the same order for the same request ID, got %+v, %v", again, err)
+	}
+
+	s.Inject(paypaltest.Fault{
+		Path:   "/v2/checkout/orders/" + order.ID,
+		Status: http.StatusUnprocessableEntity,
+		Error: paypal.ErrorResponse{Details: []paypal.ErrorResponseDetail{
+			{Issue: "PAYER_ACTION_REQUIRED"},
+		}},
+		Times: 2,
+	})
+	for i := 0; i < 2; i++ {
+		if _, err = c.GetOrder(order.ID); issue(t, err) != "PAYER_ACTION_REQUIRED" {
+			t.Fatalf("expected injected error, got %v", err)
+		}
+	}
+	if _, err = c.GetOrder(order.ID); err != nil {
+		t.Fatalf("expected fault to be removed, got %v", err)
+	}
+
+	// The expired token is rejected, renewed and the request replayed
+	s.ExpireTokens()
+	before := len(s.Requests())
+	if _, err = c.GetOrder(order.ID); err != nil {
+		t.Fatalf("expected the token to be renewed, got %v", err)
+	}
+	requests := s.Requests()[before:]
+	if len(requests) != 3 || requests[1].URL.Path != "/v1/oauth2/token" {
+		t.Fatalf("expected a rejected request, a token request and a replay, got %d requests", len(requests))
+	}
+}
+
+func TestPayouts(t *testing.T) {
+	s := paypaltest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	payout, err := c.CreateSinglePayout(paypal.Payout{
+		SenderBatchHeader: &paypal.SenderBatchHeader{EmailSubject: "Subject", SenderBatchID: "batch-1"},
+		Items: []paypal.PayoutItem{
+			{RecipientType: "EMAIL", Receiver: "a@example.com", Amount: &paypal.AmountPayout{Currency: "USD", Value: "1.50"}},
+			{RecipientType: "EMAIL", Receiver: "b@example.com", Amount: &paypal.AmountPayout{Currency: "USD", Value: "2.50"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := c.GetPayout(payout.BatchHeader.PayoutBatchID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.BatchHeader.Amount.Value != "4.00" || len(batch.Items) != 2 {
+		t.Fatalf("unexpected batch %+v", batch.BatchHeader)
+	}
+
+	claimed, cancelled := batch.Items[0].PayoutItemID, batch.Items[1].PayoutItemID
+	if err = s.ClaimPayoutItem(claimed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.CancelPayoutItem(claimed); issue(t, err) != "ITEM_CANCELLATION_FAILED" {
+		t.Fatalf("expected claimed item not to be cancelled, got %v", err)
+	}
+	item, err := c.CancelPayoutItem(cancelled)
+	if err != nil || item.TransactionStatus != "RETURNED" {
+		t.Fatalf("unexpected item %+v, %v", item, err)
+	}
+}
+
+func TestVault(t *testing.T) {
+	s := paypaltest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	if _, err := c.StoreCreditCard(paypal.CreditCard{Number: "4111111111111112", Type: "visa", ExpireMonth: "11", ExpireYear: "2030"}); issue(t, err) != "Value is invalid" {
+		t.Fatalf("expected invalid number to be rejected, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.StoreCreditCard(paypal.CreditCard{Number: "4111111111111111", Type: "visa", ExpireMonth: "11", ExpireYear: "2030"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cards, err := c.GetCreditCards(&paypal.CreditCardsFilter{Page: 2, PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cards.TotalItems != 3 || cards.TotalPages != 2 || len(cards.Items) != 1 || cards.Items[0].Number != "xxxxxxxxxxxx1111" {
+		t.Fatalf("unexpected cards %+v", cards)
+	}
+
+	id := cards.Items[0].ID
+	card, err := c.PatchCreditCard(id, []paypal.CreditCardField{{Operation: "replace", Path: "/first_name", Value: "Betsy"}})
+	if err != nil || card.FirstName != "Betsy" {
+		t.Fatalf("unexpected card %+v, %v", card, err)
+	}
+
+	if err = c.DeleteCreditCard(id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.GetCreditCard(id); issue(t, err) != "INVALID_RESOURCE_ID" {
+		t.Fatalf("expected deleted card not to be found, got %v", err)
+	}
+}
+
+func TestWebProfiles(t *testing.T) {
+	s := paypaltest.NewServer()
+	defer s.Close()
+	c := s.NewClient()
+
+	wp, err := c.CreateWebProfile(paypal.WebProfile{Name: "YeowZa! T-Shirt Shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.CreateWebProfile(paypal.WebProfile{Name: "YeowZa! T-Shirt Shop"}); err == nil {
+		t.Fatalf("expected duplicate name to be rejected")
+	}
+
+	wp.Presentation.BrandName = "YeowZa! Paypal"
+	if err = c.SetWebProfile(*wp); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetWebProfile(wp.ID)
+	if err != nil || got.Presentation.BrandName != "YeowZa! Paypal" {
+		t.Fatalf("unexpected profile %+v, %v", got, err)
+	}
+
+	if err = c.DeleteWebProfile(wp.ID); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := c.GetWebProfiles()
+	if err != nil || len(profiles) != 0 {
+		t.Fatalf("expected no profiles, got %+v, %v", profiles, err)
+	}
+}
@@ -0,0 +1,147 @@
+package paypaltest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/siriele/paypal"
+)
+
+// serveCards serves /v1/vault/credit-cards
+func (s *Server) serveCards(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		s.storeCard(w, r)
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		s.listCards(w, r)
+	case len(rest) != 1 || s.cards[rest[0]] == nil:
+		notFound(w)
+	case r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.cards[rest[0]])
+	case r.Method == http.MethodDelete:
+		delete(s.cards, rest[0])
+		for i, id := range s.cardIDs {
+			if id == rest[0] {
+				s.cardIDs = append(s.cardIDs[:i:i], s.cardIDs[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPatch:
+		s.patchCard(w, r, s.cards[rest[0]])
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) storeCard(w http.ResponseWriter, r *http.Request) {
+	cc := paypal.CreditCard{}
+	if !decode(w, r, &cc) {
+		return
+	}
+	if details := validateCard(&cc); len(details) > 0 {
+		writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request - see details.", details...)
+		return
+	}
+
+	cc.ID = s.id("CARD-", 29)
+	cc.Number = strings.Repeat("x", len(cc.Number)-4) + cc.Number[len(cc.Number)-4:]
+	cc.CVV2 = ""
+	cc.State = "ok"
+	cc.ValidUntil = s.Now().UTC().AddDate(3, 0, 0).Format("2006-01-02T15:04:05Z")
+	s.cards[cc.ID] = &cc
+	s.cardIDs = append(s.cardIDs, cc.ID)
+
+	writeJSON(w, http.StatusCreated, cc)
+}
+
+func (s *Server) listCards(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if size <= 0 {
+		size = 10
+	}
+
+	response := paypal.CreditCards{
+		Items:      []paypal.CreditCard{},
+		TotalItems: len(s.cardIDs),
+		TotalPages: (len(s.cardIDs) + size - 1) / size,
+	}
+	for i := (page - 1) * size; i < page*size && i < len(s.cardIDs); i++ {
+		response.Items = append(response.Items, *s.cards[s.cardIDs[i]])
+	}
+	if page < response.TotalPages {
+		response.Links = append(response.Links, s.link("next", http.MethodGet, fmt.Sprintf("/v1/vault/credit-cards?page_size=%d&page=%d", size, page+1)))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) patchCard(w http.ResponseWriter, r *http.Request, cc *paypal.CreditCard) {
+	var fields []paypal.CreditCardField
+	if !decode(w, r, &fields) {
+		return
+	}
+
+	updated := *cc
+	for i, f := range fields {
+		if f.Path == "/id" || f.Path == "/number" {
+			writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request - see details.",
+				paypal.ErrorResponseDetail{Field: fmt.Sprintf("[%d].path", i), Issue: f.Path + " cannot be updated"})
+			return
+		}
+		if err := patchJSON(&updated, f.Operation, f.Path, f.Value); err != nil {
+			writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request - see details.",
+				paypal.ErrorResponseDetail{Field: fmt.Sprintf("[%d].path", i), Issue: err.Error()})
+			return
+		}
+	}
+	*cc = updated
+
+	writeJSON(w, http.StatusOK, cc)
+}
+
+// validateCard returns the issues of cc, card numbers are checked with the Luhn algorithm
+func validateCard(cc *paypal.CreditCard) []paypal.ErrorResponseDetail {
+	var details []paypal.ErrorResponseDetail
+	if !luhn(cc.Number) {
+		details = append(details, paypal.ErrorResponseDetail{Field: "number", Issue: "Value is invalid"})
+	}
+	if cc.Type == "" {
+		details = append(details, paypal.ErrorResponseDetail{Field: "type", Issue: "Required field is missing"})
+	}
+	if month, err := strconv.Atoi(cc.ExpireMonth); err != nil || month < 1 || month > 12 {
+		details = append(details, paypal.ErrorResponseDetail{Field: "expire_month", Issue: "Must be a number between 1 and 12"})
+	}
+	if year, err := strconv.Atoi(cc.ExpireYear); err != nil || len(cc.ExpireYear) != 4 || year < 1900 {
+		details = append(details, paypal.ErrorResponseDetail{Field: "expire_year", Issue: "Must be a four digit year"})
+	}
+	return details
+}
+
+// luhn reports whether number is a well-formed card number
+func luhn(number string) bool {
+	if len(number) < 12 || len(number) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(number); i++ {
+		d := int(number[len(number)-1-i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
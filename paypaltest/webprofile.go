@@ -0,0 +1,69 @@
+package paypaltest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/siriele/paypal"
+)
+
+// serveProfiles serves /v1/payment-experience/web-profiles
+func (s *Server) serveProfiles(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		wp := paypal.WebProfile{}
+		if !decode(w, r, &wp) || !s.validProfile(w, &wp, "") {
+			return
+		}
+		wp.ID = s.id("XP-", 24)
+		s.profiles[wp.ID] = &wp
+		s.profileIDs = append(s.profileIDs, wp.ID)
+		writeJSON(w, http.StatusCreated, wp)
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		profiles := []paypal.WebProfile{}
+		for _, id := range s.profileIDs {
+			profiles = append(profiles, *s.profiles[id])
+		}
+		writeJSON(w, http.StatusOK, profiles)
+	case len(rest) != 1 || s.profiles[rest[0]] == nil:
+		// PayPal answers 400 for unknown profiles
+		writeError(w, http.StatusBadRequest, "INVALID_RESOURCE_ID", "The requested resource ID was not found")
+	case r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.profiles[rest[0]])
+	case r.Method == http.MethodPut:
+		wp := paypal.WebProfile{}
+		if !decode(w, r, &wp) || !s.validProfile(w, &wp, rest[0]) {
+			return
+		}
+		wp.ID = rest[0]
+		s.profiles[wp.ID] = &wp
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(s.profiles, rest[0])
+		for i, id := range s.profileIDs {
+			if id == rest[0] {
+				s.profileIDs = append(s.profileIDs[:i:i], s.profileIDs[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// validProfile checks wp has a name no other profile than id uses
+func (s *Server) validProfile(w http.ResponseWriter, wp *paypal.WebProfile, id string) bool {
+	if wp.Name == "" {
+		writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request - see details.",
+			paypal.ErrorResponseDetail{Field: "name", Issue: "Required field is missing"})
+		return false
+	}
+	for _, other := range s.profiles {
+		if other.Name == wp.Name && other.ID != id {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("A profile with name %s already exists", wp.Name))
+			return false
+		}
+	}
+	return true
+}
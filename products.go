@@ -0,0 +1,158 @@
+package paypal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type ProductType string
+
+// Possible values for `type` in Product
+//
+// https://developer.paypal.com/docs/api/catalog-products/v1/#products_create
+const (
+	ProductTypePhysical ProductType = "PHYSICAL"
+	ProductTypeDigital  ProductType = "DIGITAL"
+	ProductTypeService  ProductType = "SERVICE"
+)
+
+type (
+	// Product is a good or service of the catalog, subscription plans are created for a product
+	//
+	// https://developer.paypal.com/docs/api/catalog-products/v1/#definition-product
+	Product struct {
+		ID          string      `json:"id"`
+		Name        string      `json:"name"`
+		Description string      `json:"description,omitempty"`
+		Type        ProductType `json:"type,omitempty"`
+		Category    string      `json:"category,omitempty"`
+		ImageURL    string      `json:"image_url,omitempty"`
+		HomeURL     string      `json:"home_url,omitempty"`
+		CreateTime  PTime       `json:"create_time,omitempty"`
+		UpdateTime  PTime       `json:"update_time,omitempty"`
+		Links       []Link      `json:"links,omitempty"`
+	}
+
+	// CreateProductRequest - https://developer.paypal.com/docs/api/catalog-products/v1/#products_create
+	CreateProductRequest struct {
+		// ID is generated by PayPal when empty
+		ID          string      `json:"id,omitempty"`
+		Name        string      `json:"name"`
+		Description string      `json:"description,omitempty"`
+		Type        ProductType `json:"type"`
+		Category    string      `json:"category,omitempty"`
+		ImageURL    string      `json:"image_url,omitempty"`
+		HomeURL     string      `json:"home_url,omitempty"`
+	}
+
+	// ListProductsParams paginates ListProducts, pages are numbered from 1.
+	// Zero values are not sent
+	ListProductsParams struct {
+		Page          int
+		PageSize      int
+		TotalRequired bool
+	}
+
+	// ListProductsResponse GET /v1/catalogs/products
+	ListProductsResponse struct {
+		Products   []Product `json:"products"`
+		TotalItems int       `json:"total_items,omitempty"`
+		TotalPages int       `json:"total_pages,omitempty"`
+		Links      []Link    `json:"links,omitempty"`
+	}
+)
+
+// query returns the query string parameters of p, p may be nil
+func (p *ListProductsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Page > 0 {
+		q.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(p.PageSize))
+	}
+	if p.TotalRequired {
+		q.Set("total_required", "true")
+	}
+	return q
+}
+
+// CreateProduct adds a product to the catalog
+// Endpoint: POST /v1/catalogs/products
+func (c *Client) CreateProduct(product CreateProductRequest, opts ...RequestOption) (*Product, error) {
+	return c.CreateProductContext(context.Background(), product, opts...)
+}
+
+// CreateProductContext is like CreateProduct but uses ctx for the request
+func (c *Client) CreateProductContext(ctx context.Context, product CreateProductRequest, opts ...RequestOption) (*Product, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/catalogs/products"), product)
+	response := &Product{}
+	if err != nil {
+		return response, err
+	}
+	c.applyIdempotency(req, opts)
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// ListProducts lists the products of the catalog
+// Endpoint: GET /v1/catalogs/products
+func (c *Client) ListProducts(params *ListProductsParams) (*ListProductsResponse, error) {
+	return c.ListProductsContext(context.Background(), params)
+}
+
+// ListProductsContext is like ListProducts but uses ctx for the request
+func (c *Client) ListProductsContext(ctx context.Context, params *ListProductsParams) (*ListProductsResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/catalogs/products"), nil)
+	response := &ListProductsResponse{}
+	if err != nil {
+		return response, err
+	}
+
+	req.URL.RawQuery = params.query().Encode()
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// GetProduct shows details for a product, by ID
+// Endpoint: GET /v1/catalogs/products/ID
+func (c *Client) GetProduct(productID string) (*Product, error) {
+	return c.GetProductContext(context.Background(), productID)
+}
+
+// GetProductContext is like GetProduct but uses ctx for the request
+func (c *Client) GetProductContext(ctx context.Context, productID string) (*Product, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/catalogs/products/"+productID), nil)
+	response := &Product{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// UpdateProduct updates a product, by ID. PayPal accepts patches of the
+// description, category, image_url and home_url,
+// e.g. {Operation: "replace", Path: "/description", Value: "Premium video streaming"}
+// Endpoint: PATCH /v1/catalogs/products/ID
+func (c *Client) UpdateProduct(productID string, patches []PaymentPatch) error {
+	return c.UpdateProductContext(context.Background(), productID, patches)
+}
+
+// UpdateProductContext is like UpdateProduct but uses ctx for the request
+func (c *Client) UpdateProductContext(ctx context.Context, productID string, patches []PaymentPatch) error {
+	req, err := c.NewRequestContext(ctx, "PATCH", fmt.Sprintf("%s%s", c.APIBase, "/v1/catalogs/products/"+productID), patches)
+	if err != nil {
+		return err
+	}
+
+	return c.SendWithAuth(req, nil)
+}
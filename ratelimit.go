@@ -0,0 +1,241 @@
+package paypal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimitRecovery is how long a rate lowered after a 429 takes to climb back to its limit
+	rateLimitRecovery = 30 * time.Second
+	// rateLimitFloor divides the configured rate to give the lowest rate 429s can lower it to
+	rateLimitFloor = 16
+)
+
+type (
+	// RateLimit configures how a Client sends the requests of an endpoint group,
+	// see SetRateLimit. Requests over the limit wait for their turn, or for their
+	// context to be done, instead of failing
+	RateLimit struct {
+		// Rate is the number of requests per second, 0 means unlimited.
+		// It is halved when PayPal answers 429 and recovers within 30 seconds
+		Rate float64
+		// Burst is the number of requests which can be sent at once, 1 when unset
+		Burst int
+		// MaxInFlight caps the number of requests waiting for a response, 0 means no cap
+		MaxInFlight int
+	}
+
+	// rateLimits are the limiters of a Client by path prefix
+	rateLimits struct {
+		mu       sync.Mutex
+		limiters map[string]*limiter
+		// prefixes are the keys of limiters, longest first
+		prefixes []string
+	}
+
+	// limiter is a token bucket and a semaphore for an endpoint group
+	limiter struct {
+		limit    RateLimit
+		inFlight chan struct{}
+
+		mu     sync.Mutex
+		tokens float64
+		last   time.Time
+		// reduced is the rate after the last 429, at throttledAt
+		reduced     float64
+		throttledAt time.Time
+		// pausedUntil honors the Retry-After of the last 429
+		pausedUntil time.Time
+	}
+
+	// releaseBody is a response body calling release once it is read to the end or closed
+	releaseBody struct {
+		io.ReadCloser
+		once    sync.Once
+		release func()
+	}
+)
+
+// SetRateLimit limits the requests whose path starts with prefix, e.g.
+// "/v2/checkout/orders" or "/v1/payments/payouts-item". The longest matching
+// prefix applies, "" sets the limit of the requests matching no other prefix.
+// Passing a zero RateLimit removes the limit of prefix
+func (c *Client) SetRateLimit(prefix string, limit RateLimit) {
+	l := c.rateLimits()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit == (RateLimit{}) {
+		delete(l.limiters, prefix)
+	} else {
+		l.limiters[prefix] = newLimiter(limit)
+	}
+
+	l.prefixes = l.prefixes[:0]
+	for p := range l.limiters {
+		l.prefixes = append(l.prefixes, p)
+	}
+	sort.Slice(l.prefixes, func(i, j int) bool { return len(l.prefixes[i]) > len(l.prefixes[j]) })
+}
+
+// rateLimits returns the limiters of c
+func (c *Client) rateLimits() *rateLimits {
+	c.limitsOnce.Do(func() { c.limits = &rateLimits{limiters: map[string]*limiter{}} })
+	return c.limits
+}
+
+// limiter returns the limiter of req, nil when it is not limited
+func (c *Client) limiter(req *http.Request) *limiter {
+	l := c.rateLimits()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, p := range l.prefixes {
+		if strings.HasPrefix(req.URL.Path, p) {
+			return l.limiters[p]
+		}
+	}
+	return nil
+}
+
+// newLimiter returns a limiter for limit with a full bucket
+func newLimiter(limit RateLimit) *limiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	l := &limiter{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// wait blocks until a request can be sent or ctx is done. On success the
+// returned func must be called once the response body is consumed
+func (l *limiter) wait(ctx context.Context) (func(), error) {
+	if err := l.take(ctx); err != nil {
+		return nil, err
+	}
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.inFlight <- struct{}{}:
+		return func() { <-l.inFlight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// take takes a token from the bucket, waiting for one if needed
+func (l *limiter) take(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reserve takes a token and returns 0, or returns how long to wait for one
+func (l *limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	rate := l.rate(now)
+	if rate <= 0 {
+		return 0
+	}
+	l.tokens += now.Sub(l.last).Seconds() * rate
+	if max := float64(l.limit.Burst); l.tokens > max {
+		l.tokens = max
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / rate * float64(time.Second))
+}
+
+// rate returns the current rate, recovering linearly from the last 429
+func (l *limiter) rate(now time.Time) float64 {
+	if l.throttledAt.IsZero() {
+		return l.limit.Rate
+	}
+	recovered := float64(now.Sub(l.throttledAt)) / float64(rateLimitRecovery)
+	if recovered >= 1 {
+		return l.limit.Rate
+	}
+	return l.reduced + (l.limit.Rate-l.reduced)*recovered
+}
+
+// observe adapts the limiter to resp: a 429 halves the rate and pauses
+// requests for its Retry-After, if any
+func (l *limiter) observe(resp *http.Response) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.limit.Rate > 0 {
+		l.reduced = l.rate(now) / 2
+		if floor := l.limit.Rate / rateLimitFloor; l.reduced < floor {
+			l.reduced = floor
+		}
+		l.throttledAt = now
+		if l.tokens > 0 {
+			l.tokens = 0
+		}
+		l.last = now
+	}
+	if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok && now.Add(d).After(l.pausedUntil) {
+		l.pausedUntil = now.Add(d)
+	}
+}
+
+// Read reads the body, releasing the request once it is read to the end
+func (b *releaseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.once.Do(b.release)
+	}
+	return n, err
+}
+
+// Close closes the body and releases the request
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,166 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+	c.SetRateLimit("/v2/checkout/orders", RateLimit{Rate: 20})
+	c.SetRateLimit("/v2/checkout/orders/UNLIMITED", RateLimit{Rate: 1000, Burst: 100})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := c.GetOrder("O-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Fatalf("expected 5 requests at 20/s to take 200ms, took %v", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		c.GetOrder("UNLIMITED")
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("expected the longest prefix to apply, took %v", elapsed)
+	}
+
+	// The bucket is empty, the request gives up with its context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	before := atomic.LoadInt32(&hits)
+	if _, err := c.GetOrderContext(ctx, "O-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+	if atomic.LoadInt32(&hits) != before {
+		t.Fatalf("expected the request not to be sent")
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	var inFlight, max int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+	c.SetRateLimit("", RateLimit{MaxInFlight: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.GetPayoutItem("ITEM-1")
+		}()
+	}
+	wg.Wait()
+
+	if max != 2 {
+		t.Fatalf("expected at most 2 requests in flight, got %d", max)
+	}
+}
+
+func TestMaxInFlightUntilBodyClosed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetRateLimit("", RateLimit{MaxInFlight: 1})
+
+	req, _ := c.NewRequest("GET", ts.URL+"/v1/payments/payouts-item/ITEM-1", nil)
+	resp, err := c.do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The body of the first response is not consumed yet
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.do(req.WithContext(ctx)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request to wait for the first body, got %v", err)
+	}
+
+	resp.Body.Close()
+	resp, err = c.do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func TestForMerchantSharesRateLimits(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+	m := c.ForMerchant("MERCHANT1")
+	c.SetRateLimit("/v2/checkout/orders", RateLimit{Rate: 20})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		c.GetOrder("O-1")
+		m.GetOrder("O-2")
+	}
+	if elapsed := time.Since(start); elapsed < 230*time.Millisecond {
+		t.Fatalf("expected 6 requests at 20/s across the partner and merchant clients to take 250ms, took %v", elapsed)
+	}
+}
+
+func TestRateLimitAdapts(t *testing.T) {
+	l := newLimiter(RateLimit{Rate: 100})
+	throttled := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+
+	l.observe(throttled)
+	if rate := l.rate(time.Now()); rate < 49 || rate > 51 {
+		t.Fatalf("expected the rate to be halved, got %v", rate)
+	}
+	for i := 0; i < 10; i++ {
+		l.observe(throttled)
+	}
+	if rate := l.rate(time.Now()); rate < 100.0/rateLimitFloor || rate > 7 {
+		t.Fatalf("expected the rate to stop at its floor, got %v", rate)
+	}
+	if rate := l.rate(time.Now().Add(rateLimitRecovery)); rate != 100 {
+		t.Fatalf("expected the rate to recover, got %v", rate)
+	}
+
+	throttled.Header.Set("Retry-After", "2")
+	l.observe(throttled)
+	if delay := l.reserve(); delay < time.Second || delay > 2*time.Second {
+		t.Fatalf("expected requests to pause for Retry-After, got %v", delay)
+	}
+}
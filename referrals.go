@@ -0,0 +1,128 @@
+package paypal
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// CreateReferralResp struct
+	// Redirect the seller to the action_url link to start onboarding
+	CreateReferralResp struct {
+		Links []Link `json:"links"`
+	}
+
+	// ReferralResp GET /v2/customer/partner-referrals/ID
+	ReferralResp struct {
+		PartnerReferralID string          `json:"partner_referral_id"`
+		SubmitterPayerID  string          `json:"submitter_payer_id,omitempty"`
+		ReferralData      ReferralRequest `json:"referral_data"`
+		Links             []Link          `json:"links,omitempty"`
+	}
+
+	// MerchantIntegration GET /v1/customer/partners/ID/merchant-integrations/ID
+	MerchantIntegration struct {
+		MerchantID            string                     `json:"merchant_id"`
+		TrackingID            string                     `json:"tracking_id,omitempty"`
+		LegalName             string                     `json:"legal_name,omitempty"`
+		PrimaryEmail          string                     `json:"primary_email,omitempty"`
+		PrimaryEmailConfirmed bool                       `json:"primary_email_confirmed"`
+		PaymentsReceivable    bool                       `json:"payments_receivable"`
+		Products              []MerchantProduct          `json:"products,omitempty"`
+		Capabilities          []MerchantCapability       `json:"capabilities,omitempty"`
+		OAuthIntegrations     []MerchantOAuthIntegration `json:"oauth_integrations,omitempty"`
+		Links                 []Link                     `json:"links,omitempty"`
+	}
+
+	// MerchantProduct struct
+	MerchantProduct struct {
+		Name          string   `json:"name"`
+		VettingStatus string   `json:"vetting_status,omitempty"`
+		Capabilities  []string `json:"capabilities,omitempty"`
+	}
+
+	// MerchantCapability struct
+	MerchantCapability struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+
+	// MerchantOAuthIntegration struct
+	MerchantOAuthIntegration struct {
+		IntegrationType   string                    `json:"integration_type"`
+		IntegrationMethod string                    `json:"integration_method,omitempty"`
+		OAuthThirdParty   []MerchantOAuthThirdParty `json:"oauth_third_party,omitempty"`
+	}
+
+	// MerchantOAuthThirdParty struct
+	MerchantOAuthThirdParty struct {
+		PartnerClientID  string   `json:"partner_client_id"`
+		MerchantClientID string   `json:"merchant_client_id"`
+		Scopes           []string `json:"scopes"`
+	}
+)
+
+// ActionURL returns the href of the action_url link, where the seller signs up
+func (r *CreateReferralResp) ActionURL() string {
+	for _, l := range r.Links {
+		if l.Rel == LinkRelActionURL {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// CreatePartnerReferral creates a partner referral to onboard a seller
+// Endpoint: POST /v2/customer/partner-referrals
+func (c *Client) CreatePartnerReferral(r ReferralRequest) (*CreateReferralResp, error) {
+	return c.CreatePartnerReferralContext(context.Background(), r)
+}
+
+// CreatePartnerReferralContext is like CreatePartnerReferral but uses ctx for the request
+func (c *Client) CreatePartnerReferralContext(ctx context.Context, r ReferralRequest) (*CreateReferralResp, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/customer/partner-referrals"), r)
+	response := &CreateReferralResp{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// GetPartnerReferral shows the referral data of a partner referral, by ID
+// Endpoint: GET /v2/customer/partner-referrals/ID
+func (c *Client) GetPartnerReferral(referralID string) (*ReferralResp, error) {
+	return c.GetPartnerReferralContext(context.Background(), referralID)
+}
+
+// GetPartnerReferralContext is like GetPartnerReferral but uses ctx for the request
+func (c *Client) GetPartnerReferralContext(ctx context.Context, referralID string) (*ReferralResp, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v2/customer/partner-referrals/"+referralID), nil)
+	response := &ReferralResp{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// ShowSellerStatus shows the onboarding status of a seller for a partner:
+// whether payments are receivable, the email is confirmed and which products and permissions are granted
+// Endpoint: GET /v1/customer/partners/PARTNER_ID/merchant-integrations/MERCHANT_ID
+func (c *Client) ShowSellerStatus(partnerID, merchantID string) (*MerchantIntegration, error) {
+	return c.ShowSellerStatusContext(context.Background(), partnerID, merchantID)
+}
+
+// ShowSellerStatusContext is like ShowSellerStatus but uses ctx for the request
+func (c *Client) ShowSellerStatusContext(ctx context.Context, partnerID, merchantID string) (*MerchantIntegration, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s/v1/customer/partners/%s/merchant-integrations/%s", c.APIBase, partnerID, merchantID), nil)
+	response := &MerchantIntegration{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
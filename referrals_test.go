@@ -0,0 +1,93 @@
+package paypal
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreatePartnerReferral(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v2/customer/partner-referrals" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"tracking_id":"seller-42","email":"seller@example.com","partner_config_override":{"return_url":"https://example.com/onboarded"},"operations":[{"operation":"API_INTEGRATION"}],"products":["EXPRESS_CHECKOUT"],"legal_consents":[{"type":"SHARE_DATA_CONSENT","granted":true}]}`
+		if string(body) != expected {
+			t.Errorf("unexpected body %s", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"links":[{"href":"https://api-m.paypal.com/v2/customer/partner-referrals/ZjcyODU4ZWYtYTA1OC00ODIwLTk2M2EtOTZkZWQ4NmQwYzI3","rel":"self","method":"GET"},{"href":"https://www.paypal.com/merchantsignup/partner/onboardingentry?token=ZjcyODU4ZWY","rel":"action_url","method":"GET"}]}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	res, err := c.CreatePartnerReferral(ReferralRequest{
+		TrackingID:            "seller-42",
+		Email:                 "seller@example.com",
+		PartnerConfigOverride: &PartnerConfigOverride{ReturnURL: "https://example.com/onboarded"},
+		Operations:            []Operation{{Operation: "API_INTEGRATION"}},
+		Products:              []string{"EXPRESS_CHECKOUT"},
+		LegalConsents:         []Consent{{Type: "SHARE_DATA_CONSENT", Granted: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ActionURL() != "https://www.paypal.com/merchantsignup/partner/onboardingentry?token=ZjcyODU4ZWY" {
+		t.Fatalf("unexpected action URL %q", res.ActionURL())
+	}
+	if (&CreateReferralResp{}).ActionURL() != "" {
+		t.Fatal("expected no action URL without links")
+	}
+}
+
+func TestGetPartnerReferral(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v2/customer/partner-referrals/ZjcyODU4ZWY" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"partner_referral_id":"ZjcyODU4ZWY","submitter_payer_id":"RFYUH2QQDGUQU","referral_data":{"tracking_id":"seller-42","products":["EXPRESS_CHECKOUT"]}}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	res, err := c.GetPartnerReferral("ZjcyODU4ZWY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.PartnerReferralID != "ZjcyODU4ZWY" || res.SubmitterPayerID != "RFYUH2QQDGUQU" || res.ReferralData.TrackingID != "seller-42" || len(res.ReferralData.Products) != 1 {
+		t.Fatalf("unexpected referral %+v", res)
+	}
+}
+
+func TestShowSellerStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v1/customer/partners/RFYUH2QQDGUQU/merchant-integrations/8LQLM2ML4ZTYU" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"merchant_id":"8LQLM2ML4ZTYU","tracking_id":"seller-42","payments_receivable":true,"primary_email_confirmed":false,"products":[{"name":"PPCP_CUSTOM","vetting_status":"SUBSCRIBED","capabilities":["CUSTOM_CARD_PROCESSING"]}],"capabilities":[{"name":"CUSTOM_CARD_PROCESSING","status":"ACTIVE"}],"oauth_integrations":[{"integration_type":"OAUTH_THIRD_PARTY","oauth_third_party":[{"partner_client_id":"AXjlD","merchant_client_id":"AV3Ej","scopes":["https://uri.paypal.com/services/payments/realtimepayment"]}]}]}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	status, err := c.ShowSellerStatus("RFYUH2QQDGUQU", "8LQLM2ML4ZTYU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.PaymentsReceivable || status.PrimaryEmailConfirmed || status.TrackingID != "seller-42" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if len(status.Products) != 1 || status.Products[0].VettingStatus != "SUBSCRIBED" || len(status.Capabilities) != 1 || status.Capabilities[0].Status != "ACTIVE" {
+		t.Fatalf("unexpected products %+v", status)
+	}
+	if len(status.OAuthIntegrations) != 1 || status.OAuthIntegrations[0].OAuthThirdParty[0].MerchantClientID != "AV3Ej" {
+		t.Fatalf("unexpected oauth integrations %+v", status.OAuthIntegrations)
+	}
+}
@@ -0,0 +1,121 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// refreshAhead is how long before RequestNewTokenBeforeExpiresIn the
+	// background refresher renews a token, so requests never wait for one
+	refreshAhead = RequestNewTokenBeforeExpiresIn
+
+	// minRefreshBackoff and maxRefreshBackoff bound the delay between failed renewals
+	minRefreshBackoff = time.Second
+	maxRefreshBackoff = time.Minute
+)
+
+// ErrTokenNotRenewed is reported to the onError of a TokenRefresher when renewing
+// the token returned one expiring no later, e.g. from a TokenSource with its own cache
+var ErrTokenNotRenewed = errors.New("paypal: renewed token does not expire later")
+
+// TokenRefresher renews the access token of a Client in the background, see StartTokenRefresher
+type TokenRefresher struct {
+	c       *Client
+	onError func(error)
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// StartTokenRefresher starts renewing the access token of c before it is about
+// to expire, so SendWithAuth does not have to. Renewals go through the token
+// source of c, if any, so clients sharing a cache renew their token once.
+//
+// onError, if not nil, is called when a renewal fails, it is retried with backoff.
+// A renewal which does not extend the expiry fails with ErrTokenNotRenewed.
+// The refresher runs until ctx is done or Stop is called. It returns on its own for
+// tokens which never expire, e.g. set with SetAccessToken
+func (c *Client) StartTokenRefresher(ctx context.Context, onError func(error)) *TokenRefresher {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &TokenRefresher{
+		c:       c,
+		onError: onError,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go r.run(ctx)
+
+	return r
+}
+
+// Stop stops the refresher and waits for a renewal in progress to finish
+func (r *TokenRefresher) Stop() {
+	r.cancel()
+	<-r.done
+}
+
+// run renews the token until ctx is done
+func (r *TokenRefresher) run(ctx context.Context) {
+	defer close(r.done)
+
+	backoff := minRefreshBackoff
+	for {
+		wait, err := r.refresh(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if r.onError != nil {
+				r.onError(err)
+			}
+			wait = backoff
+			if backoff *= 2; backoff > maxRefreshBackoff {
+				backoff = maxRefreshBackoff
+			}
+		} else {
+			backoff = minRefreshBackoff
+		}
+		if wait < 0 {
+			return
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// refresh renews the token when it is about to expire and returns how long to wait
+// before the next check, or a negative duration when the token never expires
+func (r *TokenRefresher) refresh(ctx context.Context) (time.Duration, error) {
+	t, err := r.c.currentToken(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if t.Expiry.IsZero() {
+		return -1, nil
+	}
+
+	if time.Until(t.Expiry) < RequestNewTokenBeforeExpiresIn+refreshAhead {
+		renewed, err := r.c.renewToken(ctx, t.AccessToken)
+		if err != nil {
+			return 0, err
+		}
+		// Retrying right away would get the same token, back off instead
+		if !renewed.Expiry.IsZero() && !renewed.Expiry.After(t.Expiry) {
+			return 0, ErrTokenNotRenewed
+		}
+		t = renewed
+	}
+
+	wait := time.Until(t.Expiry) - RequestNewTokenBeforeExpiresIn - refreshAhead
+	if wait < minRefreshBackoff {
+		wait = minRefreshBackoff
+	}
+	return wait, nil
+}
@@ -0,0 +1,185 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenRefresher(t *testing.T) {
+	var fetches int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first token is about to expire, so it is renewed right away
+		if atomic.AddInt32(&fetches, 1) == 1 {
+			w.Write([]byte(`{"access_token":"short","expires_in":100}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"long","expires_in":32400}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	errs := make(chan error, 1)
+	r := c.StartTokenRefresher(context.Background(), func(err error) { errs <- err })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&fetches) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	r.Stop()
+
+	select {
+	case err := <-errs:
+		t.Fatal(err)
+	default:
+	}
+	c.Lock()
+	token := c.Token.Token
+	c.Unlock()
+	if token != "long" || atomic.LoadInt32(&fetches) != 2 {
+		t.Fatalf("expected the token to be renewed once, got %s after %d fetches", token, fetches)
+	}
+}
+
+// expiringSource always returns the same token, about to expire
+type expiringSource struct {
+	calls int32
+	token Token
+}
+
+func (s *expiringSource) Token(ctx context.Context) (*Token, error) {
+	atomic.AddInt32(&s.calls, 1)
+	t := s.token
+	return &t, nil
+}
+
+func TestTokenRefresherBacksOffWithoutRenewal(t *testing.T) {
+	src := &expiringSource{token: Token{AccessToken: "cached", Expiry: time.Now().Add(100 * time.Second)}}
+	c, _ := NewClient("foo", "bar", "http://127.0.0.1:0")
+	c.SetTokenSource(src)
+
+	errs := make(chan error, 10)
+	r := c.StartTokenRefresher(context.Background(), func(err error) { errs <- err })
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrTokenNotRenewed) {
+			t.Fatalf("expected ErrTokenNotRenewed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the refresher to report the token was not renewed")
+	}
+	time.Sleep(100 * time.Millisecond)
+	r.Stop()
+
+	if calls := atomic.LoadInt32(&src.calls); calls != 2 {
+		t.Fatalf("expected the refresher to back off, got %d calls", calls)
+	}
+}
+
+func TestTokenRefresherStopsOnStaticToken(t *testing.T) {
+	c, _ := NewClient("foo", "bar", "http://127.0.0.1:0")
+	c.SetAccessToken("static")
+
+	r := c.StartTokenRefresher(context.Background(), nil)
+	select {
+	case <-r.done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the refresher to return for a token which never expires")
+	}
+	r.Stop()
+}
+
+func TestSendWithAuthRenewsRejectedToken(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oauth2/token" {
+			w.Write([]byte(`{"access_token":"renewed","expires_in":32400}`))
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if r.Header.Get("Authorization") != "Bearer renewed" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_token","error_description":"Token signature verification failed"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"O-1"}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("revoked")
+
+	order, err := c.CaptureOrder("O-1", CaptureOrderRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.ID != "O-1" || len(bodies) != 2 || bodies[0] != bodies[1] {
+		t.Fatalf("expected the request to be replayed once with its body, got %q", bodies)
+	}
+
+	// The 401 is returned when the token cannot be renewed
+	c.SetAccessToken("renewed")
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	var errResp *ErrorResponse
+	if _, err = c.GetOrder("O-1"); !errors.As(err, &errResp) || errResp.Response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a 401 error, got %v", err)
+	}
+}
+
+func TestSendWithAuthDuringRenewal(t *testing.T) {
+	release := make(chan struct{})
+	var fetches int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oauth2/token" {
+			atomic.AddInt32(&fetches, 1)
+			<-release
+			w.Write([]byte(`{"access_token":"renewed","expires_in":32400}`))
+			return
+		}
+		w.Write([]byte(`{"id":"` + r.Header.Get("Authorization") + `"}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.Token = &TokenResponse{Token: "expiring"}
+	c.tokenExpiresAt = time.Now().Add(RequestNewTokenBeforeExpiresIn / 2)
+
+	renewed := make(chan string)
+	go func() {
+		order, err := c.GetOrder("O-1")
+		if err != nil {
+			t.Error(err)
+			renewed <- ""
+			return
+		}
+		renewed <- order.ID
+	}()
+	for atomic.LoadInt32(&fetches) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The token is still valid, so requests do not wait for the renewal
+	order, err := c.GetOrder("O-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.ID != "Bearer expiring" {
+		t.Fatalf("expected the current token during the renewal, got %q", order.ID)
+	}
+
+	close(release)
+	if id := <-renewed; id != "Bearer renewed" {
+		t.Fatalf("expected the renewed token, got %q", id)
+	}
+	if fetches != 1 {
+		t.Fatalf("expected a single renewal, got %d", fetches)
+	}
+}
@@ -0,0 +1,202 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryPolicy is a sensible policy for SetRetryPolicy:
+// three attempts in total, backing off from 500ms up to 10s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  10 * time.Second,
+}
+
+type (
+	// RetryPolicy configures how Send retries transient failures:
+	// network errors, 429 RATE_LIMIT_REACHED and 5xx responses.
+	//
+	// Only idempotent methods (GET, HEAD, OPTIONS, PUT, DELETE) and requests
+	// carrying a PayPal-Request-Id header are retried, so money-moving calls
+	// are never replayed without an idempotency key.
+	RetryPolicy struct {
+		// MaxAttempts is the total number of attempts, including the first one
+		MaxAttempts int
+		// MinBackoff is the base delay before the first retry, it doubles on every attempt
+		MinBackoff time.Duration
+		// MaxBackoff caps the delay between attempts, including delays asked by Retry-After
+		MaxBackoff time.Duration
+		// OnAttempt, if set, is called after every attempt
+		OnAttempt func(RetryAttempt)
+	}
+
+	// RetryAttempt describes a finished attempt passed to RetryPolicy.OnAttempt
+	RetryAttempt struct {
+		Request *http.Request
+		// Attempt is 1 for the first try
+		Attempt int
+		// Response is nil when the attempt failed with Err
+		Response *http.Response
+		Err      error
+		// Retry reports whether another attempt will be made after Delay
+		Retry bool
+		Delay time.Duration
+	}
+)
+
+// do executes req, retrying it according to c.retryPolicy
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	p := c.retryPolicy
+	for attempt := 1; ; attempt++ {
+		l := c.limiter(req)
+		var release func()
+		if l != nil {
+			var err error
+			if release, err = l.wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		start := time.Now()
+		resp, err := c.Client.Do(req)
+		c.log(req, resp, err, attempt, time.Since(start))
+		if l != nil {
+			l.observe(resp)
+			if resp == nil {
+				release()
+			} else {
+				// The request is in flight until its body is consumed
+				resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+			}
+		}
+
+		if p == nil {
+			return resp, err
+		}
+
+		retry := attempt < p.MaxAttempts && p.retryable(req, resp, err)
+		var delay time.Duration
+		if retry {
+			delay = p.backoff(attempt, resp)
+		}
+		if p.OnAttempt != nil {
+			p.OnAttempt(RetryAttempt{Request: req, Attempt: attempt, Response: resp, Err: err, Retry: retry, Delay: delay})
+		}
+		if !retry {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if req.GetBody != nil {
+			body, berr := req.GetBody()
+			if berr != nil {
+				return nil, berr
+			}
+			req.Body = body
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		case <-t.C:
+		}
+	}
+}
+
+// retryable reports whether the outcome of req is transient and req can be safely replayed
+func (p *RetryPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		if req.Header.Get(HeaderRequestID) == "" {
+			return false
+		}
+	}
+
+	if err != nil {
+		return req.Context().Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return retryableStatus(resp.StatusCode)
+}
+
+// retryableStatus reports whether a response with status is transient
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// backoff returns the delay before the attempt following attempt.
+// Retry-After is honored when present, otherwise the delay grows
+// exponentially with jitter
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && d > p.MaxBackoff {
+				d = p.MaxBackoff
+			}
+			return d
+		}
+	}
+
+	d := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// Equal jitter: keep half of the delay, randomize the other half
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// retryAfter parses a Retry-After header value given either in seconds or as an HTTP date
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,95 @@
+package paypal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_retriesTransientFailures(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id":"O-1"}`))
+	}))
+	defer ts.Close()
+
+	var attempts []RetryAttempt
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+	c.SetRetryPolicy(&RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+		OnAttempt: func(a RetryAttempt) {
+			attempts = append(attempts, a)
+		},
+	})
+
+	order, err := c.GetOrder("O-1")
+	if err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+	if order.ID != "O-1" {
+		t.Fatalf("expected order O-1, got %q", order.ID)
+	}
+	if calls != 3 || len(attempts) != 3 {
+		t.Fatalf("expected 3 calls and 3 observed attempts, got %d and %d", calls, len(attempts))
+	}
+	if !attempts[0].Retry || !attempts[1].Retry || attempts[2].Retry {
+		t.Fatalf("unexpected retry decisions: %+v", attempts)
+	}
+}
+
+func TestRetryPolicy_postRequiresRequestID(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get(HeaderRequestID) != "key-1" {
+			t.Errorf("expected request id to be replayed, got %q", r.Header.Get(HeaderRequestID))
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+	c.SetRetryPolicy(&RetryPolicy{MaxAttempts: 2})
+
+	req, _ := c.NewRequest("POST", ts.URL+"/v2/checkout/orders/O-1/capture", CaptureOrderRequest{})
+	if err := c.SendWithAuth(req, nil); err == nil {
+		t.Fatal("expected POST without PayPal-Request-Id not to be retried")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+
+	req, _ = c.NewRequest("POST", ts.URL+"/v2/checkout/orders/O-1/capture", CaptureOrderRequest{})
+	req.Header.Set(HeaderRequestID, "key-1")
+	calls = 0
+	if err := c.SendWithAuth(req, nil); err != nil {
+		t.Fatalf("expected POST with PayPal-Request-Id to be retried, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if d, ok := retryAfter("3"); !ok || d != 3*time.Second {
+		t.Errorf("expected 3s, got %v %v", d, ok)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Errorf("expected invalid Retry-After to be ignored")
+	}
+}
@@ -1,15 +1,23 @@
 package paypal
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // GetSale returns a sale by ID
 // Use this call to get details about a sale transaction.
 // Note: This call returns only the sales that were created via the REST API.
-// Endpoint: GET /v2/payments/sale/ID
+// Endpoint: GET /v1/payments/sale/ID
 func (c *Client) GetSale(saleID string) (*Sale, error) {
+	return c.GetSaleContext(context.Background(), saleID)
+}
+
+// GetSaleContext is like GetSale but uses ctx for the request
+func (c *Client) GetSaleContext(ctx context.Context, saleID string) (*Sale, error) {
 	sale := &Sale{}
 
-	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/sale/"+saleID), nil)
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/sale/"+saleID), nil)
 	if err != nil {
 		return sale, err
 	}
@@ -23,15 +31,20 @@ func (c *Client) GetSale(saleID string) (*Sale, error) {
 
 // RefundSale refunds a completed payment.
 // Use this call to refund a completed payment. Provide the sale_id in the URI and an empty JSON payload for a full refund. For partial refunds, you can include an amount.
-// Endpoint: POST /v2/payments/sale/ID/refund
+// Endpoint: POST /v1/payments/sale/ID/refund
 func (c *Client) RefundSale(saleID string, a *Amount) (*Refund, error) {
+	return c.RefundSaleContext(context.Background(), saleID, a)
+}
+
+// RefundSaleContext is like RefundSale but uses ctx for the request
+func (c *Client) RefundSaleContext(ctx context.Context, saleID string, a *Amount) (*Refund, error) {
 	type refundRequest struct {
 		Amount *Amount `json:"amount"`
 	}
 
 	refund := &Refund{}
 
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/sale/"+saleID+"/refund"), &refundRequest{Amount: a})
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/sale/"+saleID+"/refund"), &refundRequest{Amount: a})
 	if err != nil {
 		return refund, err
 	}
@@ -43,13 +56,18 @@ func (c *Client) RefundSale(saleID string, a *Amount) (*Refund, error) {
 	return refund, nil
 }
 
-// GetRefund by ID
+// GetSaleRefund returns the refund of a sale by ID
 // Use it to look up details of a specific refund on direct and captured payments.
-// Endpoint: GET /v2/payments/refund/ID
-func (c *Client) GetRefund(refundID string) (*Refund, error) {
+// Endpoint: GET /v1/payments/refund/ID
+func (c *Client) GetSaleRefund(refundID string) (*Refund, error) {
+	return c.GetSaleRefundContext(context.Background(), refundID)
+}
+
+// GetSaleRefundContext is like GetSaleRefund but uses ctx for the request
+func (c *Client) GetSaleRefundContext(ctx context.Context, refundID string) (*Refund, error) {
 	refund := &Refund{}
 
-	req, err := c.NewRequest("GET", fmt.Sprintf("%s%s", c.APIBase, "/v2/payments/refund/"+refundID), nil)
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/payments/refund/"+refundID), nil)
 	if err != nil {
 		return refund, err
 	}
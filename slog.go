@@ -0,0 +1,32 @@
+//go:build go1.21
+
+package paypal
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogLogger is a Logger writing to a *slog.Logger
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger returns a Logger writing to l, for SetLogger
+func NewSlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l: l}
+}
+
+// Enabled reports whether l handles records of level
+func (s slogLogger) Enabled(ctx context.Context, level LogLevel) bool {
+	return s.l.Enabled(ctx, slog.Level(level))
+}
+
+// Log writes a record with fields as attributes
+func (s slogLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	attrs := make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		attrs[i] = slog.Any(f.Key, f.Value)
+	}
+	s.l.LogAttrs(ctx, slog.Level(level), msg, attrs...)
+}
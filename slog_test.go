@@ -0,0 +1,44 @@
+//go:build go1.21
+
+package paypal
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderDebugID, "f05063556a338")
+		w.Write([]byte(`{"id":"CARD-1","number":"4111111111111111","cvv2":"123"}`))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("secret-token")
+	c.SetLogger(NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	if _, err := c.StoreCreditCard(CreditCard{Number: "4417119669820331", CVV2: "874"}); err != nil {
+		t.Fatal(err)
+	}
+
+	record := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record["level"] != "INFO" || record["method"] != "POST" || record["path"] != "/v1/vault/credit-cards" ||
+		record["status"] != float64(200) || record["debug_id"] != "f05063556a338" {
+		t.Fatalf("unexpected record %v", record)
+	}
+	for _, secret := range []string{"secret-token", "4417119669820331", "874", "4111111111111111", "\"123\""} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("expected %s to be redacted from %s", secret, buf.String())
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package paypal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type SubscriptionPlanStatus string
+
+// Possible values for `status` in SubscriptionPlan
+//
+// https://developer.paypal.com/docs/api/subscriptions/v1/#definition-plan
+const (
+	SubscriptionPlanStatusCreated  SubscriptionPlanStatus = "CREATED"
+	SubscriptionPlanStatusInactive SubscriptionPlanStatus = "INACTIVE"
+	SubscriptionPlanStatusActive   SubscriptionPlanStatus = "ACTIVE"
+)
+
+type IntervalUnit string
+
+// Possible values for `interval_unit` in BillingCycleFrequency
+const (
+	IntervalUnitDay   IntervalUnit = "DAY"
+	IntervalUnitWeek  IntervalUnit = "WEEK"
+	IntervalUnitMonth IntervalUnit = "MONTH"
+	IntervalUnitYear  IntervalUnit = "YEAR"
+)
+
+type TenureType string
+
+// Possible values for `tenure_type` in BillingCycle. Trial cycles come first
+const (
+	TenureTypeRegular TenureType = "REGULAR"
+	TenureTypeTrial   TenureType = "TRIAL"
+)
+
+type PricingModel string
+
+// Possible values for `pricing_model` in PricingScheme, for quantity based plans
+//
+// https://developer.paypal.com/docs/api/subscriptions/v1/#definition-pricing_scheme
+const (
+	// PricingModelVolume charges every unit the price of the tier the quantity falls in
+	PricingModelVolume PricingModel = "VOLUME"
+	// PricingModelTiered charges the units of each tier the price of that tier
+	PricingModelTiered PricingModel = "TIERED"
+)
+
+type SetupFeeFailureAction string
+
+// Possible values for `setup_fee_failure_action` in PaymentPreferences
+const (
+	SetupFeeFailureActionContinue SetupFeeFailureAction = "CONTINUE"
+	SetupFeeFailureActionCancel   SetupFeeFailureAction = "CANCEL"
+)
+
+type (
+	// SubscriptionPlan defines the billing cycles of the subscriptions to a product
+	//
+	// https://developer.paypal.com/docs/api/subscriptions/v1/#definition-plan
+	SubscriptionPlan struct {
+		ID                 string                 `json:"id"`
+		ProductID          string                 `json:"product_id"`
+		Name               string                 `json:"name"`
+		Status             SubscriptionPlanStatus `json:"status"`
+		Description        string                 `json:"description,omitempty"`
+		BillingCycles      []BillingCycle         `json:"billing_cycles,omitempty"`
+		PaymentPreferences *PaymentPreferences    `json:"payment_preferences,omitempty"`
+		Taxes              *Taxes                 `json:"taxes,omitempty"`
+		QuantitySupported  bool                   `json:"quantity_supported"`
+		CreateTime         PTime                  `json:"create_time,omitempty"`
+		UpdateTime         PTime                  `json:"update_time,omitempty"`
+		Links              []Link                 `json:"links,omitempty"`
+	}
+
+	// CreateSubscriptionPlanRequest - https://developer.paypal.com/docs/api/subscriptions/v1/#plans_create
+	CreateSubscriptionPlanRequest struct {
+		ProductID   string `json:"product_id"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		// Status is ACTIVE when empty, CREATED plans must be activated
+		Status             SubscriptionPlanStatus `json:"status,omitempty"`
+		BillingCycles      []BillingCycle         `json:"billing_cycles"`
+		PaymentPreferences *PaymentPreferences    `json:"payment_preferences"`
+		Taxes              *Taxes                 `json:"taxes,omitempty"`
+		QuantitySupported  bool                   `json:"quantity_supported,omitempty"`
+	}
+
+	// BillingCycle is a trial or regular period of a plan
+	//
+	// https://developer.paypal.com/docs/api/subscriptions/v1/#definition-billing_cycle
+	BillingCycle struct {
+		Frequency  BillingCycleFrequency `json:"frequency"`
+		TenureType TenureType            `json:"tenure_type"`
+		Sequence   int                   `json:"sequence"`
+		// TotalCycles is the number of times the cycle is billed, 0 bills regular cycles until the subscription is cancelled
+		TotalCycles   int            `json:"total_cycles"`
+		PricingScheme *PricingScheme `json:"pricing_scheme,omitempty"`
+	}
+
+	// BillingCycleFrequency is the length of a billing cycle, e.g. 3 MONTH
+	BillingCycleFrequency struct {
+		IntervalUnit  IntervalUnit `json:"interval_unit"`
+		IntervalCount int          `json:"interval_count,omitempty"`
+	}
+
+	// PricingScheme is the price of a billing cycle: a fixed price, or tiers for quantity based plans.
+	// A trial cycle without pricing scheme is free
+	//
+	// https://developer.paypal.com/docs/api/subscriptions/v1/#definition-pricing_scheme
+	PricingScheme struct {
+		Version      int           `json:"version,omitempty"`
+		FixedPrice   *Money        `json:"fixed_price,omitempty"`
+		PricingModel PricingModel  `json:"pricing_model,omitempty"`
+		Tiers        []PricingTier `json:"tiers,omitempty"`
+	}
+
+	// PricingTier prices the quantities from StartingQuantity to EndingQuantity,
+	// the last tier has no EndingQuantity
+	PricingTier struct {
+		StartingQuantity string `json:"starting_quantity"`
+		EndingQuantity   string `json:"ending_quantity,omitempty"`
+		Amount           Money  `json:"amount"`
+	}
+
+	// PaymentPreferences configure the setup fee and the failed payments of subscriptions
+	//
+	// https://developer.paypal.com/docs/api/subscriptions/v1/#definition-payment_preferences
+	PaymentPreferences struct {
+		// AutoBillOutstanding bills the outstanding balance with the next cycle, it is always sent
+		AutoBillOutstanding     bool                  `json:"auto_bill_outstanding"`
+		SetupFee                *Money                `json:"setup_fee,omitempty"`
+		SetupFeeFailureAction   SetupFeeFailureAction `json:"setup_fee_failure_action,omitempty"`
+		PaymentFailureThreshold int                   `json:"payment_failure_threshold,omitempty"`
+	}
+
+	// Taxes of a plan, Percentage is e.g. "10"
+	Taxes struct {
+		Percentage string `json:"percentage"`
+		Inclusive  bool   `json:"inclusive"`
+	}
+
+	// ListSubscriptionPlansParams filters and paginates ListSubscriptionPlans, pages are numbered from 1.
+	// Zero values are not sent
+	ListSubscriptionPlansParams struct {
+		ProductID     string
+		PlanIDs       []string
+		Page          int
+		PageSize      int
+		TotalRequired bool
+	}
+
+	// ListSubscriptionPlansResponse GET /v1/billing/plans
+	ListSubscriptionPlansResponse struct {
+		Plans      []SubscriptionPlan `json:"plans"`
+		TotalItems int                `json:"total_items,omitempty"`
+		TotalPages int                `json:"total_pages,omitempty"`
+		Links      []Link             `json:"links,omitempty"`
+	}
+
+	// PricingSchemeUpdate replaces the pricing scheme of a billing cycle, see UpdateSubscriptionPlanPricing
+	PricingSchemeUpdate struct {
+		BillingCycleSequence int           `json:"billing_cycle_sequence"`
+		PricingScheme        PricingScheme `json:"pricing_scheme"`
+	}
+)
+
+// query returns the query string parameters of p, p may be nil
+func (p *ListSubscriptionPlansParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.ProductID != "" {
+		q.Set("product_id", p.ProductID)
+	}
+	if len(p.PlanIDs) > 0 {
+		q.Set("plan_ids", strings.Join(p.PlanIDs, ","))
+	}
+	if p.Page > 0 {
+		q.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(p.PageSize))
+	}
+	if p.TotalRequired {
+		q.Set("total_required", "true")
+	}
+	return q
+}
+
+// CreateSubscriptionPlan creates a plan for a product of the catalog
+// Endpoint: POST /v1/billing/plans
+func (c *Client) CreateSubscriptionPlan(plan CreateSubscriptionPlanRequest, opts ...RequestOption) (*SubscriptionPlan, error) {
+	return c.CreateSubscriptionPlanContext(context.Background(), plan, opts...)
+}
+
+// CreateSubscriptionPlanContext is like CreateSubscriptionPlan but uses ctx for the request
+func (c *Client) CreateSubscriptionPlanContext(ctx context.Context, plan CreateSubscriptionPlanRequest, opts ...RequestOption) (*SubscriptionPlan, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/plans"), plan)
+	response := &SubscriptionPlan{}
+	if err != nil {
+		return response, err
+	}
+	c.applyIdempotency(req, opts)
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// ListSubscriptionPlans lists plans, e.g. those of a product
+// Endpoint: GET /v1/billing/plans
+func (c *Client) ListSubscriptionPlans(params *ListSubscriptionPlansParams) (*ListSubscriptionPlansResponse, error) {
+	return c.ListSubscriptionPlansContext(context.Background(), params)
+}
+
+// ListSubscriptionPlansContext is like ListSubscriptionPlans but uses ctx for the request
+func (c *Client) ListSubscriptionPlansContext(ctx context.Context, params *ListSubscriptionPlansParams) (*ListSubscriptionPlansResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/plans"), nil)
+	response := &ListSubscriptionPlansResponse{}
+	if err != nil {
+		return response, err
+	}
+
+	req.URL.RawQuery = params.query().Encode()
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// GetSubscriptionPlan shows details for a plan, by ID
+// Endpoint: GET /v1/billing/plans/ID
+func (c *Client) GetSubscriptionPlan(planID string) (*SubscriptionPlan, error) {
+	return c.GetSubscriptionPlanContext(context.Background(), planID)
+}
+
+// GetSubscriptionPlanContext is like GetSubscriptionPlan but uses ctx for the request
+func (c *Client) GetSubscriptionPlanContext(ctx context.Context, planID string) (*SubscriptionPlan, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/plans/"+planID), nil)
+	response := &SubscriptionPlan{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// UpdateSubscriptionPlan updates a plan, by ID. PayPal accepts patches of the
+// description, the payment preferences and the taxes,
+// e.g. {Operation: "replace", Path: "/payment_preferences/payment_failure_threshold", Value: 3}
+// Endpoint: PATCH /v1/billing/plans/ID
+func (c *Client) UpdateSubscriptionPlan(planID string, patches []PaymentPatch) error {
+	return c.UpdateSubscriptionPlanContext(context.Background(), planID, patches)
+}
+
+// UpdateSubscriptionPlanContext is like UpdateSubscriptionPlan but uses ctx for the request
+func (c *Client) UpdateSubscriptionPlanContext(ctx context.Context, planID string, patches []PaymentPatch) error {
+	req, err := c.NewRequestContext(ctx, "PATCH", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/plans/"+planID), patches)
+	if err != nil {
+		return err
+	}
+
+	return c.SendWithAuth(req, nil)
+}
+
+// ActivateSubscriptionPlan activates a plan, so customers can subscribe to it
+// Endpoint: POST /v1/billing/plans/ID/activate
+func (c *Client) ActivateSubscriptionPlan(planID string) error {
+	return c.ActivateSubscriptionPlanContext(context.Background(), planID)
+}
+
+// ActivateSubscriptionPlanContext is like ActivateSubscriptionPlan but uses ctx for the request
+func (c *Client) ActivateSubscriptionPlanContext(ctx context.Context, planID string) error {
+	return c.planAction(ctx, planID, "activate")
+}
+
+// DeactivateSubscriptionPlan deactivates a plan, existing subscriptions are not affected
+// Endpoint: POST /v1/billing/plans/ID/deactivate
+func (c *Client) DeactivateSubscriptionPlan(planID string) error {
+	return c.DeactivateSubscriptionPlanContext(context.Background(), planID)
+}
+
+// DeactivateSubscriptionPlanContext is like DeactivateSubscriptionPlan but uses ctx for the request
+func (c *Client) DeactivateSubscriptionPlanContext(ctx context.Context, planID string) error {
+	return c.planAction(ctx, planID, "deactivate")
+}
+
+// UpdateSubscriptionPlanPricing replaces the pricing schemes of billing cycles of a plan.
+// Subscribers are notified of price increases
+// Endpoint: POST /v1/billing/plans/ID/update-pricing-schemes
+func (c *Client) UpdateSubscriptionPlanPricing(planID string, schemes []PricingSchemeUpdate) error {
+	return c.UpdateSubscriptionPlanPricingContext(context.Background(), planID, schemes)
+}
+
+// UpdateSubscriptionPlanPricingContext is like UpdateSubscriptionPlanPricing but uses ctx for the request
+func (c *Client) UpdateSubscriptionPlanPricingContext(ctx context.Context, planID string, schemes []PricingSchemeUpdate) error {
+	type updatePricingRequest struct {
+		PricingSchemes []PricingSchemeUpdate `json:"pricing_schemes"`
+	}
+
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/plans/"+planID+"/update-pricing-schemes"), updatePricingRequest{PricingSchemes: schemes})
+	if err != nil {
+		return err
+	}
+
+	return c.SendWithAuth(req, nil)
+}
+
+// planAction posts an action without body to a plan
+func (c *Client) planAction(ctx context.Context, planID, action string) error {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/plans/"+planID+"/"+action), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.SendWithAuth(req, nil)
+}
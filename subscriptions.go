@@ -0,0 +1,354 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// LinkRelApprove is the rel of the link where the payer approves a subscription
+const LinkRelApprove string = "approve"
+
+type SubscriptionStatus string
+
+// Possible values for `status` in Subscription
+//
+// https://developer.paypal.com/docs/api/subscriptions/v1/#definition-subscription_status
+const (
+	SubscriptionStatusApprovalPending SubscriptionStatus = "APPROVAL_PENDING"
+	SubscriptionStatusApproved        SubscriptionStatus = "APPROVED"
+	SubscriptionStatusActive          SubscriptionStatus = "ACTIVE"
+	SubscriptionStatusSuspended       SubscriptionStatus = "SUSPENDED"
+	SubscriptionStatusCancelled       SubscriptionStatus = "CANCELLED"
+	SubscriptionStatusExpired         SubscriptionStatus = "EXPIRED"
+)
+
+type (
+	// Subscription of a payer to a plan
+	//
+	// https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_get
+	Subscription struct {
+		ID               string             `json:"id"`
+		PlanID           string             `json:"plan_id"`
+		Status           SubscriptionStatus `json:"status"`
+		StatusChangeNote string             `json:"status_change_note,omitempty"`
+		StatusUpdateTime PTime              `json:"status_update_time,omitempty"`
+		StartTime        PTime              `json:"start_time,omitempty"`
+		Quantity         string             `json:"quantity,omitempty"`
+		ShippingAmount   *Money             `json:"shipping_amount,omitempty"`
+		Subscriber       *Subscriber        `json:"subscriber,omitempty"`
+		BillingInfo      *BillingInfo       `json:"billing_info,omitempty"`
+		CustomID         string             `json:"custom_id,omitempty"`
+		PlanOverridden   bool               `json:"plan_overridden,omitempty"`
+		CreateTime       PTime              `json:"create_time,omitempty"`
+		UpdateTime       PTime              `json:"update_time,omitempty"`
+		Links            []Link             `json:"links,omitempty"`
+	}
+
+	// CreateSubscriptionRequest - https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_create
+	CreateSubscriptionRequest struct {
+		PlanID string `json:"plan_id"`
+		// StartTime defaults to the current time
+		StartTime          *JSONTime                       `json:"start_time,omitempty"`
+		Quantity           string                          `json:"quantity,omitempty"`
+		ShippingAmount     *Money                          `json:"shipping_amount,omitempty"`
+		Subscriber         *Subscriber                     `json:"subscriber,omitempty"`
+		CustomID           string                          `json:"custom_id,omitempty"`
+		Plan               *SubscriptionPlanOverride       `json:"plan,omitempty"`
+		ApplicationContext *SubscriptionApplicationContext `json:"application_context,omitempty"`
+	}
+
+	// ReviseSubscriptionRequest changes the plan or the quantity of a subscription.
+	// Zero values are left unchanged
+	//
+	// https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_revise
+	ReviseSubscriptionRequest struct {
+		PlanID             string                          `json:"plan_id,omitempty"`
+		Quantity           string                          `json:"quantity,omitempty"`
+		ShippingAmount     *Money                          `json:"shipping_amount,omitempty"`
+		ShippingAddress    *ShippingDetail                 `json:"shipping_address,omitempty"`
+		Plan               *SubscriptionPlanOverride       `json:"plan,omitempty"`
+		ApplicationContext *SubscriptionApplicationContext `json:"application_context,omitempty"`
+	}
+
+	// ReviseSubscriptionResponse - the payer approves the revision at its approve link, see ApproveURL
+	ReviseSubscriptionResponse struct {
+		PlanID          string          `json:"plan_id"`
+		Quantity        string          `json:"quantity,omitempty"`
+		ShippingAmount  *Money          `json:"shipping_amount,omitempty"`
+		ShippingAddress *ShippingDetail `json:"shipping_address,omitempty"`
+		PlanOverridden  bool            `json:"plan_overridden,omitempty"`
+		Links           []Link          `json:"links,omitempty"`
+	}
+
+	// SubscriptionPlanOverride overrides the billing of a plan for a single subscription
+	SubscriptionPlanOverride struct {
+		BillingCycles      []BillingCycleOverride `json:"billing_cycles,omitempty"`
+		PaymentPreferences *PaymentPreferences    `json:"payment_preferences,omitempty"`
+		Taxes              *Taxes                 `json:"taxes,omitempty"`
+	}
+
+	// BillingCycleOverride overrides the billing cycle of the plan with the same sequence
+	BillingCycleOverride struct {
+		Sequence      int            `json:"sequence"`
+		TotalCycles   *int           `json:"total_cycles,omitempty"`
+		PricingScheme *PricingScheme `json:"pricing_scheme,omitempty"`
+	}
+
+	// Subscriber is the payer of a subscription
+	Subscriber struct {
+		Name            *CreateOrderPayerName `json:"name,omitempty"`
+		EmailAddress    string                `json:"email_address,omitempty"`
+		PayerID         string                `json:"payer_id,omitempty"`
+		ShippingAddress *ShippingDetail       `json:"shipping_address,omitempty"`
+	}
+
+	// SubscriptionApplicationContext customizes the approval of a subscription by the payer
+	SubscriptionApplicationContext struct {
+		BrandName          string `json:"brand_name,omitempty"`
+		Locale             string `json:"locale,omitempty"`
+		ShippingPreference string `json:"shipping_preference,omitempty"` // GET_FROM_FILE, NO_SHIPPING, SET_PROVIDED_ADDRESS
+		UserAction         string `json:"user_action,omitempty"`         // SUBSCRIBE_NOW, CONTINUE
+		ReturnURL          string `json:"return_url,omitempty"`
+		CancelURL          string `json:"cancel_url,omitempty"`
+	}
+
+	// BillingInfo reports the payments of a subscription
+	BillingInfo struct {
+		OutstandingBalance  *Money           `json:"outstanding_balance,omitempty"`
+		CycleExecutions     []CycleExecution `json:"cycle_executions,omitempty"`
+		LastPayment         *LastPayment     `json:"last_payment,omitempty"`
+		NextBillingTime     PTime            `json:"next_billing_time,omitempty"`
+		FinalPaymentTime    PTime            `json:"final_payment_time,omitempty"`
+		FailedPaymentsCount int              `json:"failed_payments_count"`
+	}
+
+	// CycleExecution reports the billing of a cycle of a subscription
+	CycleExecution struct {
+		TenureType                  TenureType `json:"tenure_type"`
+		Sequence                    int        `json:"sequence"`
+		CyclesCompleted             int        `json:"cycles_completed"`
+		CyclesRemaining             int        `json:"cycles_remaining,omitempty"`
+		CurrentPricingSchemeVersion int        `json:"current_pricing_scheme_version,omitempty"`
+		TotalCycles                 int        `json:"total_cycles,omitempty"`
+	}
+
+	// LastPayment is the last payment of a subscription
+	LastPayment struct {
+		Amount Money `json:"amount"`
+		Time   PTime `json:"time"`
+	}
+
+	// SubscriptionTransaction is a payment of a subscription
+	//
+	// https://developer.paypal.com/docs/api/subscriptions/v1/#definition-transaction
+	SubscriptionTransaction struct {
+		ID                  string                    `json:"id"`
+		Status              string                    `json:"status"`
+		AmountWithBreakdown *TransactionAmountDetails `json:"amount_with_breakdown,omitempty"`
+		PayerName           *CreateOrderPayerName     `json:"payer_name,omitempty"`
+		PayerEmail          string                    `json:"payer_email,omitempty"`
+		Time                PTime                     `json:"time"`
+	}
+
+	// TransactionAmountDetails is the breakdown of a SubscriptionTransaction
+	TransactionAmountDetails struct {
+		GrossAmount    Money  `json:"gross_amount"`
+		FeeAmount      *Money `json:"fee_amount,omitempty"`
+		ShippingAmount *Money `json:"shipping_amount,omitempty"`
+		TaxAmount      *Money `json:"tax_amount,omitempty"`
+		NetAmount      *Money `json:"net_amount,omitempty"`
+	}
+
+	// ListSubscriptionTransactionsResponse GET /v1/billing/subscriptions/ID/transactions
+	ListSubscriptionTransactionsResponse struct {
+		Transactions []SubscriptionTransaction `json:"transactions"`
+		TotalItems   int                       `json:"total_items,omitempty"`
+		TotalPages   int                       `json:"total_pages,omitempty"`
+		Links        []Link                    `json:"links,omitempty"`
+	}
+)
+
+// ApproveURL returns the href of the approve link, where the payer approves the subscription
+func (s *Subscription) ApproveURL() string {
+	return approveURL(s.Links)
+}
+
+// ApproveURL returns the href of the approve link, where the payer approves the revision
+func (r *ReviseSubscriptionResponse) ApproveURL() string {
+	return approveURL(r.Links)
+}
+
+// approveURL returns the href of the approve link, or an empty string
+func approveURL(links []Link) string {
+	for _, l := range links {
+		if l.Rel == LinkRelApprove {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// CreateSubscription subscribes a payer to a plan. Redirect the payer to
+// its ApproveURL, the subscription starts once approved
+// Endpoint: POST /v1/billing/subscriptions
+func (c *Client) CreateSubscription(subscription CreateSubscriptionRequest, opts ...RequestOption) (*Subscription, error) {
+	return c.CreateSubscriptionContext(context.Background(), subscription, opts...)
+}
+
+// CreateSubscriptionContext is like CreateSubscription but uses ctx for the request
+func (c *Client) CreateSubscriptionContext(ctx context.Context, subscription CreateSubscriptionRequest, opts ...RequestOption) (*Subscription, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/subscriptions"), subscription)
+	response := &Subscription{}
+	if err != nil {
+		return response, err
+	}
+	c.applyIdempotency(req, opts)
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// GetSubscription shows details for a subscription, by ID
+// Endpoint: GET /v1/billing/subscriptions/ID
+func (c *Client) GetSubscription(subscriptionID string) (*Subscription, error) {
+	return c.GetSubscriptionContext(context.Background(), subscriptionID)
+}
+
+// GetSubscriptionContext is like GetSubscription but uses ctx for the request
+func (c *Client) GetSubscriptionContext(ctx context.Context, subscriptionID string) (*Subscription, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/subscriptions/"+subscriptionID), nil)
+	response := &Subscription{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// ReviseSubscription upgrades or downgrades the plan or the quantity of a subscription.
+// The payer approves the revision at the ApproveURL of the response
+// Endpoint: POST /v1/billing/subscriptions/ID/revise
+func (c *Client) ReviseSubscription(subscriptionID string, revision ReviseSubscriptionRequest) (*ReviseSubscriptionResponse, error) {
+	return c.ReviseSubscriptionContext(context.Background(), subscriptionID, revision)
+}
+
+// ReviseSubscriptionContext is like ReviseSubscription but uses ctx for the request
+func (c *Client) ReviseSubscriptionContext(ctx context.Context, subscriptionID string, revision ReviseSubscriptionRequest) (*ReviseSubscriptionResponse, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/subscriptions/"+subscriptionID+"/revise"), revision)
+	response := &ReviseSubscriptionResponse{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// SuspendSubscription suspends an active subscription, it can be activated again
+// Endpoint: POST /v1/billing/subscriptions/ID/suspend
+func (c *Client) SuspendSubscription(subscriptionID, reason string) error {
+	return c.SuspendSubscriptionContext(context.Background(), subscriptionID, reason)
+}
+
+// SuspendSubscriptionContext is like SuspendSubscription but uses ctx for the request
+func (c *Client) SuspendSubscriptionContext(ctx context.Context, subscriptionID, reason string) error {
+	return c.subscriptionAction(ctx, subscriptionID, "suspend", reason)
+}
+
+// CancelSubscription cancels a subscription for good
+// Endpoint: POST /v1/billing/subscriptions/ID/cancel
+func (c *Client) CancelSubscription(subscriptionID, reason string) error {
+	return c.CancelSubscriptionContext(context.Background(), subscriptionID, reason)
+}
+
+// CancelSubscriptionContext is like CancelSubscription but uses ctx for the request
+func (c *Client) CancelSubscriptionContext(ctx context.Context, subscriptionID, reason string) error {
+	return c.subscriptionAction(ctx, subscriptionID, "cancel", reason)
+}
+
+// ActivateSubscription activates a suspended subscription
+// Endpoint: POST /v1/billing/subscriptions/ID/activate
+func (c *Client) ActivateSubscription(subscriptionID, reason string) error {
+	return c.ActivateSubscriptionContext(context.Background(), subscriptionID, reason)
+}
+
+// ActivateSubscriptionContext is like ActivateSubscription but uses ctx for the request
+func (c *Client) ActivateSubscriptionContext(ctx context.Context, subscriptionID, reason string) error {
+	return c.subscriptionAction(ctx, subscriptionID, "activate", reason)
+}
+
+// CaptureSubscription bills the outstanding balance of a subscription, up to amount.
+// PayPal may accept the capture without returning its transaction, which is then empty
+// Endpoint: POST /v1/billing/subscriptions/ID/capture
+func (c *Client) CaptureSubscription(subscriptionID, note string, amount Money, opts ...RequestOption) (*SubscriptionTransaction, error) {
+	return c.CaptureSubscriptionContext(context.Background(), subscriptionID, note, amount, opts...)
+}
+
+// CaptureSubscriptionContext is like CaptureSubscription but uses ctx for the request
+func (c *Client) CaptureSubscriptionContext(ctx context.Context, subscriptionID, note string, amount Money, opts ...RequestOption) (*SubscriptionTransaction, error) {
+	type captureRequest struct {
+		Note        string `json:"note"`
+		CaptureType string `json:"capture_type"`
+		Amount      Money  `json:"amount"`
+	}
+
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/subscriptions/"+subscriptionID+"/capture"),
+		captureRequest{Note: note, CaptureType: "OUTSTANDING_BALANCE", Amount: amount})
+	response := &SubscriptionTransaction{}
+	if err != nil {
+		return response, err
+	}
+	c.applyIdempotency(req, opts)
+
+	// 202 Accepted may come without body
+	body := &bytes.Buffer{}
+	if err = c.SendWithAuth(req, body); err != nil {
+		return response, err
+	}
+	if body.Len() > 0 {
+		err = json.Unmarshal(body.Bytes(), response)
+	}
+	return response, err
+}
+
+// ListSubscriptionTransactions lists the transactions of a subscription between start and end
+// Endpoint: GET /v1/billing/subscriptions/ID/transactions
+func (c *Client) ListSubscriptionTransactions(subscriptionID string, start, end time.Time) (*ListSubscriptionTransactionsResponse, error) {
+	return c.ListSubscriptionTransactionsContext(context.Background(), subscriptionID, start, end)
+}
+
+// ListSubscriptionTransactionsContext is like ListSubscriptionTransactions but uses ctx for the request
+func (c *Client) ListSubscriptionTransactionsContext(ctx context.Context, subscriptionID string, start, end time.Time) (*ListSubscriptionTransactionsResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/subscriptions/"+subscriptionID+"/transactions"), nil)
+	response := &ListSubscriptionTransactionsResponse{}
+	if err != nil {
+		return response, err
+	}
+
+	q := url.Values{}
+	q.Set("start_time", start.UTC().Format(DateFormat))
+	q.Set("end_time", end.UTC().Format(DateFormat))
+	req.URL.RawQuery = q.Encode()
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// subscriptionAction posts the reason of a status change to a subscription
+func (c *Client) subscriptionAction(ctx context.Context, subscriptionID, action, reason string) error {
+	type reasonRequest struct {
+		Reason string `json:"reason"`
+	}
+
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/billing/subscriptions/"+subscriptionID+"/"+action), reasonRequest{Reason: reason})
+	if err != nil {
+		return err
+	}
+
+	return c.SendWithAuth(req, nil)
+}
@@ -0,0 +1,169 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateSubscriptionPlan(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/billing/plans" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		// total_cycles 0 bills until cancelled and must be sent, PayPal defaults to 1
+		if !strings.Contains(string(body), `"total_cycles":0`) {
+			t.Errorf("expected total_cycles to be sent, got %s", body)
+		}
+
+		plan := CreateSubscriptionPlanRequest{}
+		json.Unmarshal(body, &plan)
+		if len(plan.BillingCycles) != 2 || plan.BillingCycles[1].PricingScheme.Tiers[1].EndingQuantity != "" {
+			t.Errorf("unexpected plan %s", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"P-5ML4271244454362WXNWU5NQ","product_id":"PROD-XXCD1234QWER65782","name":"Video Streaming","status":"ACTIVE","billing_cycles":[{"frequency":{"interval_unit":"MONTH","interval_count":1},"tenure_type":"TRIAL","sequence":1,"total_cycles":1}],"create_time":"2020-02-27T12:13:51Z"}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	plan, err := c.CreateSubscriptionPlan(CreateSubscriptionPlanRequest{
+		ProductID: "PROD-XXCD1234QWER65782",
+		Name:      "Video Streaming",
+		BillingCycles: []BillingCycle{
+			{Frequency: BillingCycleFrequency{IntervalUnit: IntervalUnitMonth, IntervalCount: 1}, TenureType: TenureTypeTrial, Sequence: 1, TotalCycles: 1},
+			{Frequency: BillingCycleFrequency{IntervalUnit: IntervalUnitMonth, IntervalCount: 1}, TenureType: TenureTypeRegular, Sequence: 2, PricingScheme: &PricingScheme{
+				PricingModel: PricingModelTiered,
+				Tiers: []PricingTier{
+					{StartingQuantity: "1", EndingQuantity: "10", Amount: Money{Currency: "USD", Value: "10.00"}},
+					{StartingQuantity: "11", Amount: Money{Currency: "USD", Value: "8.00"}},
+				},
+			}},
+		},
+		PaymentPreferences: &PaymentPreferences{AutoBillOutstanding: true, PaymentFailureThreshold: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.ID != "P-5ML4271244454362WXNWU5NQ" || plan.Status != SubscriptionPlanStatusActive || plan.BillingCycles[0].TenureType != TenureTypeTrial || plan.CreateTime.IsZero() {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+}
+
+func TestSubscriptionLifecycle(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+
+		switch r.URL.Path {
+		case "/v1/billing/subscriptions":
+			if r.Header.Get(HeaderRequestID) != "sub-1" {
+				t.Errorf("expected the request ID to be sent")
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"I-BW452GLLEP1G","plan_id":"P-5ML4271244454362WXNWU5NQ","status":"APPROVAL_PENDING","links":[{"href":"https://www.paypal.com/webapps/billing/subscriptions?ba_token=BA-2M539689T3856352J","rel":"approve","method":"GET"}]}`))
+		case "/v1/billing/subscriptions/I-BW452GLLEP1G/transactions":
+			if q := r.URL.Query(); q.Get("start_time") != "2020-01-01T00:00:00.000Z" || q.Get("end_time") != "2020-02-01T00:00:00.000Z" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"transactions":[{"id":"TRFGHNJKOIIOJKL","status":"COMPLETED","amount_with_breakdown":{"gross_amount":{"currency_code":"USD","value":"10.00"}},"time":"2020-01-15T09:00:00Z"}]}`))
+		default:
+			// suspend, activate, capture and cancel answer without body
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	sub, err := c.CreateSubscription(CreateSubscriptionRequest{
+		PlanID:     "P-5ML4271244454362WXNWU5NQ",
+		Subscriber: &Subscriber{EmailAddress: "customer@example.com"},
+		ApplicationContext: &SubscriptionApplicationContext{
+			UserAction: "SUBSCRIBE_NOW",
+			ReturnURL:  "https://example.com/return",
+		},
+	}, WithRequestID("sub-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Status != SubscriptionStatusApprovalPending || !strings.Contains(sub.ApproveURL(), "BA-2M539689T3856352J") {
+		t.Fatalf("unexpected subscription %+v", sub)
+	}
+
+	if err := c.SuspendSubscription(sub.ID, "Out of stock"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ActivateSubscription(sub.ID, "Back in stock"); err != nil {
+		t.Fatal(err)
+	}
+	capture, err := c.CaptureSubscription(sub.ID, "Outstanding balance", Money{Currency: "USD", Value: "10.00"})
+	if err != nil || capture.ID != "" {
+		t.Fatalf("unexpected capture %+v, error %v", capture, err)
+	}
+	if err := c.CancelSubscription(sub.ID, "Not satisfied"); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := c.ListSubscriptionTransactions(sub.ID, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs.Transactions) != 1 || txs.Transactions[0].AmountWithBreakdown.GrossAmount.Value != "10.00" {
+		t.Fatalf("unexpected transactions %+v", txs)
+	}
+
+	expected := []string{
+		`POST /v1/billing/subscriptions/I-BW452GLLEP1G/suspend {"reason":"Out of stock"}`,
+		`POST /v1/billing/subscriptions/I-BW452GLLEP1G/activate {"reason":"Back in stock"}`,
+		`POST /v1/billing/subscriptions/I-BW452GLLEP1G/capture {"note":"Outstanding balance","capture_type":"OUTSTANDING_BALANCE","amount":{"currency_code":"USD","value":"10.00"}}`,
+		`POST /v1/billing/subscriptions/I-BW452GLLEP1G/cancel {"reason":"Not satisfied"}`,
+	}
+	for i, e := range expected {
+		if calls[i+1] != e {
+			t.Errorf("expected call %s, got %s", e, calls[i+1])
+		}
+	}
+}
+
+func TestListProductsIterator(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/catalogs/products" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`{"products":[{"id":"PROD-1","name":"Video"},{"id":"PROD-2","name":"Music"}],"links":[{"href":"https://api.paypal.com/v1/catalogs/products?page_size=2&page=2","rel":"next"}]}`))
+		case "2":
+			w.Write([]byte(`{"products":[{"id":"PROD-3","name":"Books"}]}`))
+		default:
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	var ids []string
+	it := c.ListProductsIterator(context.Background(), &ListProductsParams{Page: 1, PageSize: 2})
+	for it.Next() {
+		ids = append(ids, it.Item().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "PROD-1,PROD-2,PROD-3" {
+		t.Fatalf("unexpected products %v", ids)
+	}
+}
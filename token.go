@@ -0,0 +1,329 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var (
+	// errEmptyToken is returned when the token endpoint answers without an access token
+	errEmptyToken = errors.New("paypal: empty access token")
+	// errRefreshPanicked is returned to the callers waiting for a refresh which panicked
+	errRefreshPanicked = errors.New("paypal: token refresh panicked")
+)
+
+type (
+	// Token is an OAuth2 access token
+	Token struct {
+		AccessToken string `json:"access_token"`
+		Type        string `json:"token_type,omitempty"`
+		// Expiry is when the token expires, the zero time means it never does
+		Expiry time.Time `json:"expiry,omitempty"`
+	}
+
+	// TokenSource returns the access tokens SendWithAuth authorizes requests with.
+	// Token must be safe for concurrent use
+	TokenSource interface {
+		Token(ctx context.Context) (*Token, error)
+	}
+
+	// TokenCache stores access tokens so they are shared by clients, within a
+	// process or across processes. Implement it to keep tokens in an external
+	// store such as Redis or memcached.
+	//
+	// Load returns nil and no error when no token is stored for key
+	TokenCache interface {
+		Load(ctx context.Context, key string) (*Token, error)
+		Store(ctx context.Context, key string, t *Token) error
+	}
+
+	// MemoryTokenCache is a TokenCache for the clients of a process
+	MemoryTokenCache struct {
+		mu     sync.Mutex
+		tokens map[string]Token
+	}
+
+	// FileTokenCache is a TokenCache kept in a JSON file, for processes sharing a disk.
+	// The file is replaced atomically on every Store and only readable by its owner
+	FileTokenCache struct {
+		mu   sync.Mutex
+		path string
+	}
+
+	// clientCredentials fetches tokens with the client ID and secret of a Client
+	clientCredentials struct {
+		c *Client
+	}
+
+	// cachedTokenSource returns the tokens of a cache, refreshing them from src
+	cachedTokenSource struct {
+		src   TokenSource
+		cache TokenCache
+		key   string
+		// id identifies the refreshes shared with other sources
+		id flightKey
+	}
+
+	// flightKey identifies a token in a cache: sources storing their tokens under
+	// the same key of the same cache share their refreshes
+	flightKey struct {
+		cache interface{}
+		key   string
+	}
+
+	// tokenRenewer is implemented by the token sources able to replace a token
+	// before it expires, or after PayPal rejected it
+	tokenRenewer interface {
+		renew(ctx context.Context, stale string) (*Token, error)
+	}
+
+	// tokenFlight is a token refresh callers wait for
+	tokenFlight struct {
+		done  chan struct{}
+		token *Token
+		err   error
+	}
+)
+
+var (
+	// flights are the refreshes in progress by cache and key, shared by every
+	// Client of the process so a key is only refreshed once at a time
+	flights   = map[flightKey]*tokenFlight{}
+	flightsMu sync.Mutex
+)
+
+// Valid reports whether t is set and does not expire within RequestNewTokenBeforeExpiresIn
+func (t *Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Until(t.Expiry) >= RequestNewTokenBeforeExpiresIn
+}
+
+// NewMemoryTokenCache returns an empty MemoryTokenCache
+func NewMemoryTokenCache() *MemoryTokenCache {
+	return &MemoryTokenCache{tokens: map[string]Token{}}
+}
+
+// Load returns the token stored for key
+func (m *MemoryTokenCache) Load(ctx context.Context, key string) (*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.tokens[key]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// Store stores t for key
+func (m *MemoryTokenCache) Store(ctx context.Context, key string, t *Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[key] = *t
+	return nil
+}
+
+// NewFileTokenCache returns a FileTokenCache kept in the file at path,
+// which is created on the first Store
+func NewFileTokenCache(path string) *FileTokenCache {
+	return &FileTokenCache{path: path}
+}
+
+// Load returns the token stored for key
+func (f *FileTokenCache) Load(ctx context.Context, key string) (*Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tokens, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := tokens[key]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// Store stores t for key, dropping the expired tokens of other keys
+func (f *FileTokenCache) Store(ctx context.Context, key string, t *Token) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	for k, other := range tokens {
+		if !other.Expiry.IsZero() && other.Expiry.Before(time.Now()) {
+			delete(tokens, k)
+		}
+	}
+	tokens[key] = *t
+
+	b, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// read returns the tokens of the file, none when it does not exist
+func (f *FileTokenCache) read() (map[string]Token, error) {
+	tokens := map[string]Token{}
+	b, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// CachedTokenSource returns a TokenSource which shares the tokens of src through cache under key.
+// Tokens are taken from cache while they are valid. A single refresh from src runs
+// at a time for a key in the process, concurrent callers wait for its result
+func CachedTokenSource(src TokenSource, cache TokenCache, key string) TokenSource {
+	s := &cachedTokenSource{src: src, cache: cache, key: key}
+	s.id = flightKey{cache: cache, key: key}
+	if !reflect.TypeOf(cache).Comparable() {
+		// A cache which cannot be told apart from others does not share its refreshes
+		s.id.cache = s
+	}
+	return s
+}
+
+// Token returns the cached token, refreshing it when it is about to expire
+func (s *cachedTokenSource) Token(ctx context.Context) (*Token, error) {
+	if t, err := s.cache.Load(ctx, s.key); err == nil && t.Valid() {
+		return t, nil
+	}
+	return s.flight(ctx, "")
+}
+
+// renew returns a token other than stale, refreshing it unless another
+// client already replaced stale in the cache
+func (s *cachedTokenSource) renew(ctx context.Context, stale string) (*Token, error) {
+	return s.flight(ctx, stale)
+}
+
+// flight refreshes the token, or waits for the refresh of the key in progress.
+// A waiter whose ctx is still live takes over when the refresh it waited for
+// failed because the ctx of its caller ended
+func (s *cachedTokenSource) flight(ctx context.Context, stale string) (*Token, error) {
+	for {
+		flightsMu.Lock()
+		f, ok := flights[s.id]
+		if !ok {
+			f = &tokenFlight{done: make(chan struct{})}
+			flights[s.id] = f
+		}
+		flightsMu.Unlock()
+
+		if ok {
+			select {
+			case <-f.done:
+				if ctx.Err() == nil && (errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) {
+					continue
+				}
+				return f.token, f.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		// Waiters get errRefreshPanicked if refresh does not return
+		f.err = errRefreshPanicked
+		func() {
+			defer func() {
+				flightsMu.Lock()
+				delete(flights, s.id)
+				flightsMu.Unlock()
+				close(f.done)
+			}()
+			f.token, f.err = s.refresh(ctx, stale)
+		}()
+
+		return f.token, f.err
+	}
+}
+
+// refresh gets a token from src and stores it, unless another process
+// stored a valid token other than stale meanwhile
+func (s *cachedTokenSource) refresh(ctx context.Context, stale string) (*Token, error) {
+	if t, err := s.cache.Load(ctx, s.key); err == nil && t.Valid() && t.AccessToken != stale {
+		return t, nil
+	}
+
+	t, err := s.src.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// A failing cache costs a refresh on the next call, the token is still good
+	s.cache.Store(ctx, s.key, t)
+
+	return t, nil
+}
+
+// Token fetches a new access token from /v1/oauth2/token
+func (s clientCredentials) Token(ctx context.Context) (*Token, error) {
+	response, err := s.c.fetchToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return response.token(), nil
+}
+
+// token returns r as a Token expiring ExpiresIn seconds from now
+func (r *TokenResponse) token() *Token {
+	t := &Token{AccessToken: r.Token, Type: r.Type}
+	if r.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return t
+}
+
+// ClientCredentials returns a TokenSource fetching a new token from /v1/oauth2/token
+// with the client ID and secret of c on every call, to be wrapped in CachedTokenSource
+func (c *Client) ClientCredentials() TokenSource {
+	return clientCredentials{c: c}
+}
+
+// SetTokenSource makes SendWithAuth authorize requests with the tokens of ts
+// instead of refreshing Token itself. Passing nil restores the default
+func (c *Client) SetTokenSource(ts TokenSource) {
+	c.Lock()
+	defer c.Unlock()
+	c.tokenSource = ts
+}
+
+// SetTokenCache shares the tokens of c through cache with the other clients using
+// the same cache, API base and client ID. Tokens are refreshed with ClientCredentials
+func (c *Client) SetTokenCache(cache TokenCache) {
+	c.SetTokenSource(CachedTokenSource(c.ClientCredentials(), cache, c.APIBase+" "+c.ClientID))
+}
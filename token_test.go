@@ -0,0 +1,241 @@
+package paypal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingSource returns tokens numbered by call, once release is closed
+type countingSource struct {
+	calls   int32
+	release chan struct{}
+	expiry  time.Duration
+}
+
+func (s *countingSource) Token(ctx context.Context) (*Token, error) {
+	n := atomic.AddInt32(&s.calls, 1)
+	if s.release != nil {
+		<-s.release
+	}
+	return &Token{AccessToken: "token-" + strconv.Itoa(int(n)), Expiry: time.Now().Add(s.expiry)}, nil
+}
+
+func TestCachedTokenSourceSingleFlight(t *testing.T) {
+	src := &countingSource{release: make(chan struct{}), expiry: time.Hour}
+	ts := CachedTokenSource(src, NewMemoryTokenCache(), "key")
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 10)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tok, err := ts.Token(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			tokens[i] = tok.AccessToken
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(src.release)
+	wg.Wait()
+
+	if src.calls != 1 {
+		t.Fatalf("expected a single refresh, got %d", src.calls)
+	}
+	for _, tok := range tokens {
+		if tok != "token-1" {
+			t.Fatalf("expected every caller to get token-1, got %v", tokens)
+		}
+	}
+}
+
+// abandonedSource blocks its first call until ctx is done
+type abandonedSource struct {
+	calls int32
+}
+
+func (s *abandonedSource) Token(ctx context.Context) (*Token, error) {
+	if atomic.AddInt32(&s.calls, 1) == 1 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &Token{AccessToken: "token-2", Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+func TestCachedTokenSourceLeaderCancelled(t *testing.T) {
+	src := &abandonedSource{}
+	ts := CachedTokenSource(src, NewMemoryTokenCache(), "cancelled")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error)
+	go func() {
+		_, err := ts.Token(ctx)
+		leader <- err
+	}()
+	for atomic.LoadInt32(&src.calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiter := make(chan string)
+	go func() {
+		tok, err := ts.Token(context.Background())
+		if err != nil {
+			t.Error(err)
+			waiter <- ""
+			return
+		}
+		waiter <- tok.AccessToken
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-leader; err != context.Canceled {
+		t.Fatalf("expected the leader to be cancelled, got %v", err)
+	}
+	if tok := <-waiter; tok != "token-2" {
+		t.Fatalf("expected the waiter to refresh the token itself, got %q", tok)
+	}
+}
+
+func TestCachedTokenSourceSeparateCaches(t *testing.T) {
+	src := &countingSource{release: make(chan struct{}), expiry: time.Hour}
+	first := CachedTokenSource(src, NewMemoryTokenCache(), "shared")
+	second := CachedTokenSource(src, NewMemoryTokenCache(), "shared")
+
+	var wg sync.WaitGroup
+	for _, ts := range []TokenSource{first, second} {
+		wg.Add(1)
+		go func(ts TokenSource) {
+			defer wg.Done()
+			if _, err := ts.Token(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}(ts)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(src.release)
+	wg.Wait()
+
+	if src.calls != 2 {
+		t.Fatalf("expected a refresh per cache, got %d", src.calls)
+	}
+}
+
+// panickingSource panics once release is closed
+type panickingSource struct {
+	calls   int32
+	release chan struct{}
+}
+
+func (s *panickingSource) Token(ctx context.Context) (*Token, error) {
+	atomic.AddInt32(&s.calls, 1)
+	<-s.release
+	panic("token source failure")
+}
+
+func TestCachedTokenSourceLeaderPanics(t *testing.T) {
+	src := &panickingSource{release: make(chan struct{})}
+	ts := CachedTokenSource(src, NewMemoryTokenCache(), "panics")
+
+	leader := make(chan interface{})
+	go func() {
+		defer func() { leader <- recover() }()
+		ts.Token(context.Background())
+	}()
+	for atomic.LoadInt32(&src.calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiter := make(chan error)
+	go func() {
+		_, err := ts.Token(context.Background())
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(src.release)
+
+	if p := <-leader; p == nil {
+		t.Fatal("expected the leader to panic")
+	}
+	select {
+	case err := <-waiter:
+		if err != errRefreshPanicked {
+			t.Fatalf("expected errRefreshPanicked, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter blocked after the leader panicked")
+	}
+}
+
+func TestSetTokenCache(t *testing.T) {
+	var fetches int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oauth2/token" {
+			atomic.AddInt32(&fetches, 1)
+			w.Write([]byte(`{"access_token":"shared","token_type":"Bearer","expires_in":32400}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer shared" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	cache := NewMemoryTokenCache()
+	for i := 0; i < 3; i++ {
+		c, _ := NewClient("foo", "bar", ts.URL)
+		c.SetTokenCache(cache)
+		if _, err := c.GetOrder("O-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf("expected clients to share one token, got %d fetches", fetches)
+	}
+}
+
+func TestFileTokenCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	src := &countingSource{expiry: 30 * time.Second}
+	ts := CachedTokenSource(src, NewFileTokenCache(path), "key")
+
+	tok, err := ts.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the cache to be private, got %v", info.Mode())
+	}
+
+	// The token expires within RequestNewTokenBeforeExpiresIn, so it is refreshed
+	if tok, err = CachedTokenSource(src, NewFileTokenCache(path), "key").Token(context.Background()); err != nil || tok.AccessToken != "token-2" {
+		t.Fatalf("expected a refreshed token, got %+v, %v", tok, err)
+	}
+
+	src.expiry = time.Hour
+	CachedTokenSource(src, NewFileTokenCache(path), "key").Token(context.Background())
+	loaded, err := NewFileTokenCache(path).Load(context.Background(), "key")
+	if err != nil || loaded.AccessToken != "token-3" || !loaded.Valid() {
+		t.Fatalf("expected the stored token, got %+v, %v", loaded, err)
+	}
+	if src.calls != 3 {
+		t.Fatalf("expected 3 refreshes, got %d", src.calls)
+	}
+}
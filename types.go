@@ -70,6 +70,7 @@ type ItemCategory string
 const (
 	ItemCategoryDigitalGood  ItemCategory = "DIGITAL_GOODS"
 	ItemCategoryPhysicalGood ItemCategory = "PHYSICAL_GOODS"
+	ItemCategoryDonation     ItemCategory = "DONATION"
 )
 
 // Possible values for `shipping_preference` in ApplicationContext
@@ -81,10 +82,22 @@ const (
 	ShippingPreferenceSetProvidedAddress string = "SET_PROVIDED_ADDRESS"
 )
 
+// Possible values for `event_type` in WebhookEvent
+//
+// https://developer.paypal.com/docs/integration/direct/webhooks/event-names/
 const (
 	EventPaymentCaptureCompleted       string = "PAYMENT.CAPTURE.COMPLETED"
 	EventPaymentCaptureDenied          string = "PAYMENT.CAPTURE.DENIED"
+	EventPaymentCapturePending         string = "PAYMENT.CAPTURE.PENDING"
 	EventPaymentCaptureRefunded        string = "PAYMENT.CAPTURE.REFUNDED"
+	EventPaymentCaptureReversed        string = "PAYMENT.CAPTURE.REVERSED"
+	EventPaymentAuthorizationCreated   string = "PAYMENT.AUTHORIZATION.CREATED"
+	EventPaymentAuthorizationVoided    string = "PAYMENT.AUTHORIZATION.VOIDED"
+	EventCheckoutOrderApproved         string = "CHECKOUT.ORDER.APPROVED"
+	EventCheckoutOrderCompleted        string = "CHECKOUT.ORDER.COMPLETED"
+	EventCustomerDisputeCreated        string = "CUSTOMER.DISPUTE.CREATED"
+	EventCustomerDisputeUpdated        string = "CUSTOMER.DISPUTE.UPDATED"
+	EventCustomerDisputeResolved       string = "CUSTOMER.DISPUTE.RESOLVED"
 	EventMerchantOnboardingCompleted   string = "MERCHANT.ONBOARDING.COMPLETED"
 	EventMerchantPartnerConsentRevoked string = "MERCHANT.PARTNER-CONSENT.REVOKED"
 )
@@ -249,6 +262,41 @@ type (
 		PaymentSource *PaymentSource `json:"payment_source"`
 	}
 
+	// ConfirmPaymentSourceRequest - https://developer.paypal.com/docs/api/orders/v2/#orders_confirm
+	ConfirmPaymentSourceRequest struct {
+		PaymentSource      *PaymentSource      `json:"payment_source"`
+		ApplicationContext *ApplicationContext `json:"application_context,omitempty"`
+	}
+
+	// OrderTrackerRequest - https://developer.paypal.com/docs/api/orders/v2/#orders_track_create
+	OrderTrackerRequest struct {
+		CaptureID        string             `json:"capture_id"`
+		TrackingNumber   string             `json:"tracking_number"`
+		Carrier          string             `json:"carrier"`
+		CarrierNameOther string             `json:"carrier_name_other,omitempty"`
+		NotifyPayer      bool               `json:"notify_payer,omitempty"`
+		Items            []OrderTrackerItem `json:"items,omitempty"`
+	}
+
+	// OrderTrackerItem is a shipped item of an order tracker
+	OrderTrackerItem struct {
+		Name     string `json:"name,omitempty"`
+		Quantity string `json:"quantity,omitempty"`
+		SKU      string `json:"sku,omitempty"`
+		URL      string `json:"url,omitempty"`
+		ImageURL string `json:"image_url,omitempty"`
+	}
+
+	// OrderTracker is the tracking information of a shipment of a purchase unit
+	OrderTracker struct {
+		ID         string             `json:"id,omitempty"`
+		Status     TrackingStatus     `json:"status,omitempty"`
+		Items      []OrderTrackerItem `json:"items,omitempty"`
+		Links      []Link             `json:"links,omitempty"`
+		CreateTime PTime              `json:"create_time,omitempty"`
+		UpdateTime PTime              `json:"update_time,omitempty"`
+	}
+
 	// BatchHeader struct
 	BatchHeader struct {
 		Amount            *AmountPayout      `json:"amount,omitempty"`
@@ -277,21 +325,26 @@ type (
 		Name                string               `json:"name,omitempty"`
 		Description         string               `json:"description,omitempty"`
 		Type                string               `json:"type,omitempty"`
+		State               string               `json:"state,omitempty"`
 		PaymentDefinitions  []PaymentDefinition  `json:"payment_definitions,omitempty"`
 		MerchantPreferences *MerchantPreferences `json:"merchant_preferences,omitempty"`
+		Links               []Link               `json:"links,omitempty"`
 	}
 
 	// Capture struct
 	Capture struct {
-		Amount         *Amount                    `json:"amount,omitempty"`
-		IsFinalCapture bool                       `json:"final_capture"`
-		CreateTime     PTime                      `json:"create_time,omitempty"`
-		UpdateTime     PTime                      `json:"update_time,omitempty"`
-		Status         CaptureStatus              `json:"status,omitempty"`
-		ID             string                     `json:"id,omitempty"`
-		InvoiceID      string                     `json:"invoice_id,omitempty"`
-		Links          []Link                     `json:"links,omitempty"`
-		Breakdown      *SellerReceivableBreakdown `json:"seller_receivable_breakdown,omitempty"`
+		Amount           *Amount                    `json:"amount,omitempty"`
+		IsFinalCapture   bool                       `json:"final_capture"`
+		CreateTime       PTime                      `json:"create_time,omitempty"`
+		UpdateTime       PTime                      `json:"update_time,omitempty"`
+		Status           CaptureStatus              `json:"status,omitempty"`
+		StatusDetails    *CaptureStatusDetails      `json:"status_details,omitempty"`
+		ID               string                     `json:"id,omitempty"`
+		InvoiceID        string                     `json:"invoice_id,omitempty"`
+		CustomID         string                     `json:"custom_id,omitempty"`
+		SellerProtection *SellerProtection          `json:"seller_protection,omitempty"`
+		Links            []Link                     `json:"links,omitempty"`
+		Breakdown        *SellerReceivableBreakdown `json:"seller_receivable_breakdown,omitempty"`
 	}
 
 	// ChargeModel struct
@@ -308,8 +361,22 @@ type (
 		Secret         string
 		APIBase        string
 		Log            io.Writer // If user set log file name all requests will be logged there
+		logger         Logger
 		Token          *TokenResponse
 		tokenExpiresAt time.Time
+		tokenSource    TokenSource
+		// renewal is the request for a new Token in progress, if any
+		renewal     *tokenFlight
+		retryPolicy *RetryPolicy
+		// headers are sent with every authorized request, e.g. PayPal-Auth-Assertion
+		headers    http.Header
+		limitsOnce sync.Once
+		limits     *rateLimits
+		// middlewares wrap Send, see Use
+		middlewareMu sync.Mutex
+		middlewares  []Middleware
+		// requestIDGenerator generates PayPal-Request-Id for calls moving money
+		requestIDGenerator func() string
 	}
 
 	// CreditCard struct
@@ -378,9 +445,12 @@ type (
 
 	// ErrorResponseDetail struct
 	ErrorResponseDetail struct {
-		Field string `json:"field"`
-		Issue string `json:"issue"`
-		Links []Link `json:"link"`
+		Field       string `json:"field"`
+		Value       string `json:"value,omitempty"`
+		Location    string `json:"location,omitempty"`
+		Issue       string `json:"issue"`
+		Description string `json:"description,omitempty"`
+		Links       []Link `json:"links"`
 	}
 
 	// ErrorResponse https://developer.paypal.com/docs/api/errors/
@@ -391,12 +461,14 @@ type (
 		Message         string                `json:"message"`
 		InformationLink string                `json:"information_link"`
 		Details         []ErrorResponseDetail `json:"details"`
+		Links           []Link                `json:"links,omitempty"`
 	}
 
 	// ExecuteAgreementResponse struct
 	ExecuteAgreementResponse struct {
 		ID               string           `json:"id"`
 		State            string           `json:"state"`
+		Name             string           `json:"name,omitempty"`
 		Description      string           `json:"description,omitempty"`
 		Payer            Payer            `json:"payer"`
 		Plan             BillingPlan      `json:"plan"`
@@ -568,15 +640,24 @@ type (
 
 	// PurchaseUnitRequest struct
 	PurchaseUnitRequest struct {
-		ReferenceID    string              `json:"reference_id,omitempty"`
-		Amount         *PurchaseUnitAmount `json:"amount"`
-		Payee          *PayeeForOrders     `json:"payee,omitempty"`
-		Description    string              `json:"description,omitempty"`
-		CustomID       string              `json:"custom_id,omitempty"`
-		InvoiceID      string              `json:"invoice_id,omitempty"`
-		SoftDescriptor string              `json:"soft_descriptor,omitempty"`
-		Items          []Item              `json:"items,omitempty"`
-		Shipping       *ShippingDetail     `json:"shipping,omitempty"`
+		ReferenceID        string              `json:"reference_id,omitempty"`
+		Amount             *PurchaseUnitAmount `json:"amount"`
+		Payee              *PayeeForOrders     `json:"payee,omitempty"`
+		Description        string              `json:"description,omitempty"`
+		CustomID           string              `json:"custom_id,omitempty"`
+		InvoiceID          string              `json:"invoice_id,omitempty"`
+		SoftDescriptor     string              `json:"soft_descriptor,omitempty"`
+		Items              []Item              `json:"items,omitempty"`
+		Shipping           *ShippingDetail     `json:"shipping,omitempty"`
+		PaymentInstruction *PaymentInstruction `json:"payment_instruction,omitempty"`
+	}
+
+	// CreateOrderRequest is the body of POST /v2/checkout/orders
+	CreateOrderRequest struct {
+		Intent             PaymentIntent         `json:"intent"`
+		Payer              *CreateOrderPayer     `json:"payer,omitempty"`
+		PurchaseUnits      []PurchaseUnitRequest `json:"purchase_units"`
+		ApplicationContext *ApplicationContext   `json:"application_context,omitempty"`
 	}
 
 	// MerchantPreferences struct
@@ -595,6 +676,7 @@ type (
 		Status        OrderStatus    `json:"status,omitempty"`
 		Intent        PaymentIntent  `json:"intent,omitempty"`
 		PurchaseUnits []PurchaseUnit `json:"purchase_units,omitempty"`
+		PaymentSource *PaymentSource `json:"payment_source,omitempty"`
 		Links         []Link         `json:"links,omitempty"`
 		CreateTime    PTime          `json:"create_time,omitempty"`
 		UpdateTime    PTime          `json:"update_time,omitempty"`
@@ -675,7 +757,7 @@ type (
 	PaymentPatch struct {
 		Operation string      `json:"op"`
 		Path      string      `json:"path"`
-		Value     interface{} `json:"value"`
+		Value     interface{} `json:"value,omitempty"`
 	}
 
 	// PaymentPayer struct
@@ -797,8 +879,10 @@ type (
 		// The net amount that the payee's account is debited, if the payee holds funds in the currency for this refund. The net amount is calculated as gross_amount minus paypal_fee minus platform_fees.
 		// Read only.
 		NetAmount *Money `json:"net_amount,omitempty"`
-
-		PlatformFees []PlatformFee
+		// platform_fees array
+		// An array of platform or partner fees, commissions, or brokerage fees for the refund.
+		// Read only.
+		PlatformFees []PlatformFee `json:"platform_fees,omitempty"`
 	}
 
 	RefundRequest struct {
@@ -884,8 +968,9 @@ type (
 
 	// ShippingDetail struct
 	ShippingDetail struct {
-		Name    *Name                          `json:"name,omitempty"`
-		Address *ShippingDetailAddressPortable `json:"address,omitempty"`
+		Name     *Name                          `json:"name,omitempty"`
+		Address  *ShippingDetailAddressPortable `json:"address,omitempty"`
+		Trackers []OrderTracker                 `json:"trackers,omitempty"`
 	}
 
 	expirationTime int64
@@ -1016,6 +1101,7 @@ type (
 		// merchant-onboarding Resource type
 		PartnerClientID string `json:"partner_client_id,omitempty"`
 		MerchantID      string `json:"merchant_id,omitempty"`
+		TrackingID      string `json:"tracking_id,omitempty"`
 		// Common
 		Links []Link `json:"links,omitempty"`
 	}
@@ -1028,10 +1114,21 @@ type (
 	}
 
 	ReferralRequest struct {
-		TrackingID    string      `json:"tracking_id"`
-		Operations    []Operation `json:"operations,omitempty"`
-		Products      []string    `json:"products,omitempty"`
-		LegalConsents []Consent   `json:"legal_consents,omitempty"`
+		TrackingID            string                 `json:"tracking_id"`
+		Email                 string                 `json:"email,omitempty"`
+		PreferredLanguageCode string                 `json:"preferred_language_code,omitempty"`
+		PartnerConfigOverride *PartnerConfigOverride `json:"partner_config_override,omitempty"`
+		Operations            []Operation            `json:"operations,omitempty"`
+		Products              []string               `json:"products,omitempty"`
+		LegalConsents         []Consent              `json:"legal_consents,omitempty"`
+	}
+
+	// PartnerConfigOverride overrides the partner configuration for a single referral
+	PartnerConfigOverride struct {
+		PartnerLogoURL       string `json:"partner_logo_url,omitempty"`
+		ReturnURL            string `json:"return_url,omitempty"`
+		ReturnURLDescription string `json:"return_url_description,omitempty"`
+		ActionRenewalURL     string `json:"action_renewal_url,omitempty"`
 	}
 
 	Operation struct {
@@ -1391,6 +1488,9 @@ const (
 	WHResourceTypeRefund        WHResourceType = "refund"
 	WHResourceTypeAuthorization WHResourceType = "authorization"
 	WHResourceTypeDispute       WHResourceType = "dispute"
+	// WHResourceTypeMerchantOnboarding is sent with MERCHANT.ONBOARDING.COMPLETED
+	// and MERCHANT.PARTNER-CONSENT.REVOKED
+	WHResourceTypeMerchantOnboarding WHResourceType = "merchant-onboarding"
 )
 
 type DisputeStage string
@@ -1459,6 +1559,10 @@ const (
 	// including the current state of the resource.
 	HeaderPreferRepresentation = "return=representation"
 	HeaderPrefer               = "Prefer"
+	// HeaderRequestID carries the idempotency key of a request.
+	// PayPal returns the original result when a request is replayed with
+	// the same key, which makes retrying a POST safe.
+	HeaderRequestID = "PayPal-Request-Id"
 )
 
 type TrackingStatus string
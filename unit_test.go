@@ -1,7 +1,9 @@
 package paypal
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -59,7 +61,7 @@ func TestTypeItem(t *testing.T) {
     "name":"Item",
     "price":"22.99",
     "currency":"GBP",
-    "quantity":1
+    "quantity":"1"
 }`
 
 	i := &Item{}
@@ -71,7 +73,7 @@ func TestTypeItem(t *testing.T) {
 	if i.Name != "Item" ||
 		i.Price != "22.99" ||
 		i.Currency != "GBP" ||
-		i.Quantity != 1 {
+		i.Quantity != "1" {
 		t.Errorf("Item decoded result is incorrect, Given: %v", i)
 	}
 }
@@ -753,3 +755,113 @@ func TestDeleteWebProfile_invalid(t *testing.T) {
 	}
 
 }
+
+func TestGetOrderContext_cancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s, context was cancelled", r.URL.Path)
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.GetOrderContext(ctx, "O-1")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRequestIDs(t *testing.T) {
+	var got []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(HeaderRequestID))
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	c.CaptureOrder("O-1", CaptureOrderRequest{})
+	c.SetRequestIDGenerator(func() string { return "generated" })
+	c.CaptureOrder("O-1", CaptureOrderRequest{})
+	c.RefundCapture("C-1", &RefundRequest{}, WithRequestID("explicit"))
+	c.GetOrder("O-1")
+
+	expected := []string{"", "generated", "explicit", ""}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d requests, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("request %d: expected PayPal-Request-Id %q, got %q", i, expected[i], got[i])
+		}
+	}
+
+	if id := NewRequestID(); len(id) != 36 || id == NewRequestID() {
+		t.Errorf("expected unique UUIDs, got %s", id)
+	}
+}
+
+func TestPaymentsEndpoints(t *testing.T) {
+	var got []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"id":"R-1","status":"COMPLETED","seller_payable_breakdown":{"platform_fees":[{"amount":{"currency_code":"USD","value":"1.00"}}]}}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	c.GetSale("S-1")
+	c.RefundSale("S-1", nil)
+	c.GetSaleRefund("R-1")
+	c.GetCapture("C-1")
+	refund, err := c.GetRefund("R-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refund.Breakdown == nil || len(refund.Breakdown.PlatformFees) != 1 || refund.Breakdown.PlatformFees[0].Amount.Value != "1.00" {
+		t.Errorf("unexpected refund %+v", refund)
+	}
+
+	expected := []string{
+		"GET /v1/payments/sale/S-1",
+		"POST /v1/payments/sale/S-1/refund",
+		"GET /v1/payments/refund/R-1",
+		"GET /v2/payments/captures/C-1",
+		"GET /v2/payments/refunds/R-1",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("request %d: expected %s, got %s", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestMoneyConversions(t *testing.T) {
+	a, err := (&PurchaseUnitAmount{Currency: "USD", Value: "7.5"}).Decimal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := NewMoney(a); m.Currency != "USD" || m.Value != "7.50" {
+		t.Errorf("unexpected money %+v", m)
+	}
+	if p := NewAmountPayout(a); p.Currency != "USD" || p.Value != "7.50" {
+		t.Errorf("unexpected payout amount %+v", p)
+	}
+
+	if _, err = (&Money{Currency: "JPY", Value: "10.5"}).Decimal(); err == nil {
+		t.Errorf("expected decimals to be rejected for JPY")
+	}
+	if _, err = (*Amount)(nil).Decimal(); err == nil {
+		t.Errorf("expected an error for a nil amount")
+	}
+}
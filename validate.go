@@ -0,0 +1,328 @@
+package paypal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/siriele/paypal/money"
+)
+
+// Issues reported by Validate, named as PayPal names them in ErrorResponseDetail
+const (
+	IssueMissingRequiredParameter = "MISSING_REQUIRED_PARAMETER"
+	IssueInvalidParameterValue    = "INVALID_PARAMETER_VALUE"
+	IssueInvalidParameterSyntax   = "INVALID_PARAMETER_SYNTAX"
+	IssueInvalidStringLength      = "INVALID_STRING_LENGTH"
+	IssueDecimalPrecision         = "DECIMAL_PRECISION"
+	IssueCannotBeZeroOrNegative   = "CANNOT_BE_ZERO_OR_NEGATIVE"
+	IssueCannotBeNegative         = "CANNOT_BE_NEGATIVE"
+	IssueCurrencyMismatch         = "CURRENCY_MISMATCH"
+	IssueAmountMismatch           = "AMOUNT_MISMATCH"
+	IssueItemTotalRequired        = "ITEM_TOTAL_REQUIRED"
+	IssueItemTotalMismatch        = "ITEM_TOTAL_MISMATCH"
+	IssueTaxTotalRequired         = "TAX_TOTAL_REQUIRED"
+	IssueTaxTotalMismatch         = "TAX_TOTAL_MISMATCH"
+	IssueReferenceIDRequired      = "REFERENCE_ID_REQUIRED"
+	IssueDuplicateReferenceID     = "DUPLICATE_REFERENCE_ID"
+)
+
+// ValidationError lists the issues found in a request before sending it.
+// Its details are shaped like those of the ErrorResponse PayPal would answer
+type ValidationError struct {
+	Details []ErrorResponseDetail
+}
+
+// Error method implementation for ValidationError struct
+func (e *ValidationError) Error() string {
+	issues := make([]string, len(e.Details))
+	for i, d := range e.Details {
+		issues[i] = d.Field + " " + d.Issue
+	}
+	return "paypal: invalid request: " + strings.Join(issues, ", ")
+}
+
+// ValidatePurchaseUnits validates the purchase units of an order, see PurchaseUnitRequest.Validate.
+// Orders with several purchase units need a distinct reference_id for each of them
+func ValidatePurchaseUnits(units []PurchaseUnitRequest) error {
+	v := &validator{}
+	if len(units) == 0 {
+		v.add("/purchase_units", "", IssueMissingRequiredParameter, "At least one purchase unit is required.")
+	}
+
+	seen := map[string]bool{}
+	for i := range units {
+		ref := units[i].ReferenceID
+		if len(units) > 1 && ref == "" {
+			v.add(fmt.Sprintf("/purchase_units/%d/reference_id", i), "", IssueReferenceIDRequired, "reference_id is required when there are multiple purchase units.")
+		}
+		if ref != "" && seen[ref] {
+			v.add(fmt.Sprintf("/purchase_units/%d/reference_id", i), ref, IssueDuplicateReferenceID, "reference_id must be unique across purchase units.")
+		}
+		seen[ref] = true
+
+		units[i].validate(v)
+	}
+
+	return v.err()
+}
+
+// Validate checks r as PayPal does when creating an order: field lengths,
+// amount syntax and decimals, currency consistency, and that the breakdown
+// adds up to the amount and the items to the breakdown.
+// It returns a *ValidationError
+func (r *PurchaseUnitRequest) Validate() error {
+	v := &validator{}
+	r.validate(v)
+	return v.err()
+}
+
+// Validate checks the fields of an item of an order, the paths of the
+// issues are relative to the item, e.g. /quantity.
+// It returns a *ValidationError
+func (i *Item) Validate() error {
+	v := &validator{}
+	i.validate(v, "", "")
+	return v.err()
+}
+
+// validator collects the issues of a request
+type validator struct {
+	details []ErrorResponseDetail
+}
+
+func (v *validator) add(field, value, issue, description string) {
+	v.details = append(v.details, ErrorResponseDetail{
+		Field:       field,
+		Value:       value,
+		Location:    "body",
+		Issue:       issue,
+		Description: description,
+	})
+}
+
+// length checks s is at most max characters, and not empty when required
+func (v *validator) length(field, s string, max int, required bool) {
+	n := utf8.RuneCountInString(s)
+	switch {
+	case n == 0 && required:
+		v.add(field, "", IssueMissingRequiredParameter, "A required field is missing.")
+	case n > max:
+		v.add(field, s, IssueInvalidStringLength, fmt.Sprintf("The value of the field should not exceed %d characters.", max))
+	}
+}
+
+// amount parses value in currency, reporting issues with field
+func (v *validator) amount(field, currency, value string) (money.Amount, bool) {
+	if currency == "" {
+		v.add(field+"/currency_code", "", IssueMissingRequiredParameter, "A required field is missing.")
+		return money.Amount{}, false
+	}
+	a, err := money.Parse(value, currency)
+	switch {
+	case errors.Is(err, money.ErrInvalidCurrency):
+		v.add(field+"/currency_code", currency, IssueInvalidParameterValue, "Currency code should be a three-character currency code.")
+	case errors.Is(err, money.ErrPrecision):
+		v.add(field+"/value", value, IssueDecimalPrecision, fmt.Sprintf("%s supports %d decimal places.", currency, money.Decimals(currency)))
+	case err != nil && value == "":
+		v.add(field+"/value", "", IssueMissingRequiredParameter, "A required field is missing.")
+	case err != nil:
+		v.add(field+"/value", value, IssueInvalidParameterSyntax, "The value of a field does not conform to the expected format.")
+	}
+	return a, err == nil
+}
+
+// money parses an amount of the breakdown or of an item, which must be in
+// currency and not negative. A nil m is zero
+func (v *validator) money(field string, m *Money, currency string) (money.Amount, bool) {
+	if m == nil {
+		return money.Amount{}, true
+	}
+	a, ok := v.amount(field, m.Currency, m.Value)
+	if !ok {
+		return a, false
+	}
+	if m.Currency != currency {
+		v.add(field+"/currency_code", m.Currency, IssueCurrencyMismatch, "Should have same currency code as the amount.")
+		return a, false
+	}
+	if a.Sign() < 0 {
+		v.add(field+"/value", m.Value, IssueCannotBeNegative, "Must be greater than or equal to 0.")
+		return a, false
+	}
+	return a, true
+}
+
+func (v *validator) err() error {
+	if len(v.details) == 0 {
+		return nil
+	}
+	return &ValidationError{Details: v.details}
+}
+
+// validate checks r, reporting issues under the path PayPal uses for it
+func (r *PurchaseUnitRequest) validate(v *validator) {
+	path := purchaseUnitPath(r.ReferenceID)
+
+	v.length(path+"/reference_id", r.ReferenceID, 256, false)
+	v.length(path+"/description", r.Description, 127, false)
+	v.length(path+"/custom_id", r.CustomID, 127, false)
+	v.length(path+"/invoice_id", r.InvoiceID, 127, false)
+	v.length(path+"/soft_descriptor", r.SoftDescriptor, 22, false)
+
+	if r.Amount == nil {
+		v.add(path+"/amount", "", IssueMissingRequiredParameter, "A required field is missing.")
+		for i := range r.Items {
+			r.Items[i].validate(v, fmt.Sprintf("%s/items/%d", path, i), "")
+		}
+		return
+	}
+
+	currency := r.Amount.Currency
+	amount, amountOK := v.amount(path+"/amount", currency, r.Amount.Value)
+	if amountOK && amount.Sign() <= 0 {
+		v.add(path+"/amount/value", r.Amount.Value, IssueCannotBeZeroOrNegative, "Must be greater than zero.")
+	}
+
+	// Sum the items, ok stays true as long as every total can be trusted
+	ok := amountOK
+	var itemTotal, taxTotal money.Amount
+	taxed := false
+	for i := range r.Items {
+		item := &r.Items[i]
+		price, tax, quantity, itemOK := item.validate(v, fmt.Sprintf("%s/items/%d", path, i), currency)
+		if !itemOK {
+			ok = false
+			continue
+		}
+		taxed = taxed || item.Tax != nil
+
+		line, err := price.Mul(quantity)
+		if err == nil {
+			itemTotal, err = itemTotal.Add(line)
+		}
+		if err == nil && item.Tax != nil {
+			if line, err = tax.Mul(quantity); err == nil {
+				taxTotal, err = taxTotal.Add(line)
+			}
+		}
+		if err != nil {
+			ok = false
+		}
+	}
+
+	b := r.Amount.Breakdown
+	if b == nil {
+		if len(r.Items) > 0 {
+			v.add(path+"/amount/breakdown/item_total", "", IssueItemTotalRequired, "If item details are specified (items.unit_amount and items.quantity) corresponding amount.breakdown.item_total is required.")
+		}
+		return
+	}
+
+	field := path + "/amount/breakdown/"
+	parts := []struct {
+		name string
+		m    *Money
+		sign int64
+	}{
+		{"item_total", b.ItemTotal, 1},
+		{"tax_total", b.TaxTotal, 1},
+		{"shipping", b.Shipping, 1},
+		{"handling", b.Handling, 1},
+		{"insurance", b.Insurance, 1},
+		{"shipping_discount", b.ShippingDiscount, -1},
+		{"discount", b.Discount, -1},
+	}
+	var total money.Amount
+	for _, p := range parts {
+		a, partOK := v.money(field+p.name, p.m, currency)
+		if !partOK {
+			ok = false
+			continue
+		}
+		if p.sign < 0 {
+			a = a.Neg()
+		}
+		var err error
+		if total, err = total.Add(a); err != nil {
+			ok = false
+		}
+	}
+
+	if len(r.Items) > 0 && b.ItemTotal == nil {
+		v.add(field+"item_total", "", IssueItemTotalRequired, "If item details are specified (items.unit_amount and items.quantity) corresponding amount.breakdown.item_total is required.")
+		ok = false
+	}
+	if taxed && b.TaxTotal == nil {
+		v.add(field+"tax_total", "", IssueTaxTotalRequired, "If item details are specified (items.tax_total and items.quantity) corresponding amount.breakdown.tax_total is required.")
+		ok = false
+	}
+	if !ok {
+		return
+	}
+
+	if len(r.Items) > 0 {
+		if declared, _ := b.ItemTotal.Decimal(); !declared.Equal(itemTotal) {
+			v.add(field+"item_total/value", b.ItemTotal.Value, IssueItemTotalMismatch, fmt.Sprintf("Should equal sum of (unit_amount * quantity) across all items, %s.", itemTotal.Value()))
+		}
+	}
+	if taxed {
+		if declared, _ := b.TaxTotal.Decimal(); !declared.Equal(taxTotal) {
+			v.add(field+"tax_total/value", b.TaxTotal.Value, IssueTaxTotalMismatch, fmt.Sprintf("Should equal sum of (tax * quantity) across all items, %s.", taxTotal.Value()))
+		}
+	}
+	if !total.Equal(amount) {
+		v.add(path+"/amount/value", r.Amount.Value, IssueAmountMismatch, fmt.Sprintf("Should equal item_total + tax_total + shipping + handling + insurance - shipping_discount - discount, %s.", total.Value()))
+	}
+}
+
+// purchaseUnitPath returns the path of the fields of the purchase unit identified by referenceID
+func purchaseUnitPath(referenceID string) string {
+	if referenceID == "" {
+		referenceID = "default"
+	}
+	return fmt.Sprintf("/purchase_units/@reference_id=='%s'", referenceID)
+}
+
+// validate checks i, returning its unit amount, tax and quantity when they are valid.
+// An empty currency skips the currency check
+func (i *Item) validate(v *validator, path, currency string) (price, tax money.Amount, quantity int64, ok bool) {
+	v.length(path+"/name", i.Name, 127, true)
+	v.length(path+"/description", i.Description, 127, false)
+	v.length(path+"/sku", i.SKU, 127, false)
+
+	switch i.Category {
+	case "", ItemCategoryDigitalGood, ItemCategoryPhysicalGood, ItemCategoryDonation:
+	default:
+		v.add(path+"/category", string(i.Category), IssueInvalidParameterValue, "Should be one of DIGITAL_GOODS, PHYSICAL_GOODS or DONATION.")
+	}
+
+	ok = true
+	switch {
+	case i.Quantity == "":
+		v.add(path+"/quantity", "", IssueMissingRequiredParameter, "A required field is missing.")
+		ok = false
+	case len(i.Quantity) > 10 || strings.Trim(i.Quantity, "0123456789") != "":
+		v.add(path+"/quantity", i.Quantity, IssueInvalidParameterSyntax, "Should be a whole number of at most 10 digits.")
+		ok = false
+	default:
+		fmt.Sscan(i.Quantity, &quantity)
+		if quantity == 0 {
+			v.add(path+"/quantity", i.Quantity, IssueCannotBeZeroOrNegative, "Must be greater than zero.")
+			ok = false
+		}
+	}
+
+	if i.UnitAmount == nil {
+		v.add(path+"/unit_amount", "", IssueMissingRequiredParameter, "A required field is missing.")
+		return price, tax, quantity, false
+	}
+	if currency == "" {
+		currency = i.UnitAmount.Currency
+	}
+	price, priceOK := v.money(path+"/unit_amount", i.UnitAmount, currency)
+	tax, taxOK := v.money(path+"/tax", i.Tax, currency)
+
+	return price, tax, quantity, ok && priceOK && taxOK
+}
@@ -0,0 +1,101 @@
+package paypal
+
+import (
+	"errors"
+	"testing"
+)
+
+// issues returns the field and issue of every detail of a ValidationError
+func issues(t *testing.T, err error) map[string]string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+	found := map[string]string{}
+	for _, d := range verr.Details {
+		found[d.Field] = d.Issue
+	}
+	return found
+}
+
+func TestPurchaseUnitRequestValidate(t *testing.T) {
+	valid := func() PurchaseUnitRequest {
+		return PurchaseUnitRequest{
+			ReferenceID: "ref",
+			Amount: &PurchaseUnitAmount{
+				Currency: "USD",
+				Value:    "27.50",
+				Breakdown: &PurchaseUnitAmountBreakdown{
+					ItemTotal: &Money{Currency: "USD", Value: "20.00"},
+					TaxTotal:  &Money{Currency: "USD", Value: "2.00"},
+					Shipping:  &Money{Currency: "USD", Value: "6.50"},
+					Discount:  &Money{Currency: "USD", Value: "1"},
+				},
+			},
+			Items: []Item{
+				{Name: "Shirt", Quantity: "2", UnitAmount: &Money{Currency: "USD", Value: "7.50"}, Tax: &Money{Currency: "USD", Value: "1.00"}},
+				{Name: "Sticker", Quantity: "1", UnitAmount: &Money{Currency: "USD", Value: "5.00"}},
+			},
+		}
+	}
+
+	pu := valid()
+	if err := pu.Validate(); err != nil {
+		t.Fatalf("expected valid purchase unit, got %v", err)
+	}
+
+	const path = "/purchase_units/@reference_id=='ref'"
+	tests := []struct {
+		name     string
+		change   func(pu *PurchaseUnitRequest)
+		field    string
+		expected string
+	}{
+		{"amount mismatch", func(pu *PurchaseUnitRequest) { pu.Amount.Value = "28.00" }, path + "/amount/value", IssueAmountMismatch},
+		{"item total mismatch", func(pu *PurchaseUnitRequest) { pu.Items[1].Quantity = "2" }, path + "/amount/breakdown/item_total/value", IssueItemTotalMismatch},
+		{"tax total mismatch", func(pu *PurchaseUnitRequest) { pu.Items[0].Tax.Value = "1.50" }, path + "/amount/breakdown/tax_total/value", IssueTaxTotalMismatch},
+		{"item total required", func(pu *PurchaseUnitRequest) { pu.Amount.Breakdown.ItemTotal = nil }, path + "/amount/breakdown/item_total", IssueItemTotalRequired},
+		{"currency mismatch", func(pu *PurchaseUnitRequest) { pu.Amount.Breakdown.Shipping.Currency = "EUR" }, path + "/amount/breakdown/shipping/currency_code", IssueCurrencyMismatch},
+		{"precision", func(pu *PurchaseUnitRequest) { pu.Amount.Value = "27.501" }, path + "/amount/value", IssueDecimalPrecision},
+		{"quantity", func(pu *PurchaseUnitRequest) { pu.Items[0].Quantity = "two" }, path + "/items/0/quantity", IssueInvalidParameterSyntax},
+		{"name", func(pu *PurchaseUnitRequest) { pu.Items[1].Name = "" }, path + "/items/1/name", IssueMissingRequiredParameter},
+		{"soft descriptor", func(pu *PurchaseUnitRequest) { pu.SoftDescriptor = "MUCH TOO LONG DESCRIPTOR" }, path + "/soft_descriptor", IssueInvalidStringLength},
+	}
+
+	for _, tt := range tests {
+		pu := valid()
+		tt.change(&pu)
+		found := issues(t, pu.Validate())
+		if found[tt.field] != tt.expected {
+			t.Errorf("%s: expected %s on %s, got %v", tt.name, tt.expected, tt.field, found)
+		}
+	}
+}
+
+func TestValidatePurchaseUnits(t *testing.T) {
+	amount := &PurchaseUnitAmount{Currency: "JPY", Value: "1000"}
+	units := []PurchaseUnitRequest{
+		{ReferenceID: "a", Amount: amount},
+		{ReferenceID: "a", Amount: amount},
+		{Amount: &PurchaseUnitAmount{Currency: "JPY", Value: "10.50"}},
+	}
+
+	found := issues(t, ValidatePurchaseUnits(units))
+	expected := map[string]string{
+		"/purchase_units/1/reference_id":                        IssueDuplicateReferenceID,
+		"/purchase_units/2/reference_id":                        IssueReferenceIDRequired,
+		"/purchase_units/@reference_id=='default'/amount/value": IssueDecimalPrecision,
+	}
+	if len(found) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, found)
+	}
+	for field, issue := range expected {
+		if found[field] != issue {
+			t.Errorf("expected %s on %s, got %v", issue, field, found)
+		}
+	}
+}
@@ -1,13 +1,19 @@
 package paypal
 
 import (
+	"context"
 	"fmt"
 )
 
 // StoreCreditCard func
 // Endpoint: POST /v1/vault/credit-cards
 func (c *Client) StoreCreditCard(cc CreditCard) (*CreditCard, error) {
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/vault/credit-cards"), cc)
+	return c.StoreCreditCardContext(context.Background(), cc)
+}
+
+// StoreCreditCardContext is like StoreCreditCard but uses ctx for the request
+func (c *Client) StoreCreditCardContext(ctx context.Context, cc CreditCard) (*CreditCard, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/vault/credit-cards"), cc)
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +30,12 @@ func (c *Client) StoreCreditCard(cc CreditCard) (*CreditCard, error) {
 // DeleteCreditCard func
 // Endpoint: DELETE /v1/vault/credit-cards/credit_card_id
 func (c *Client) DeleteCreditCard(id string) error {
-	req, err := c.NewRequest("DELETE", fmt.Sprintf("%s/v1/vault/credit-cards/%s", c.APIBase, id), nil)
+	return c.DeleteCreditCardContext(context.Background(), id)
+}
+
+// DeleteCreditCardContext is like DeleteCreditCard but uses ctx for the request
+func (c *Client) DeleteCreditCardContext(ctx context.Context, id string) error {
+	req, err := c.NewRequestContext(ctx, "DELETE", fmt.Sprintf("%s/v1/vault/credit-cards/%s", c.APIBase, id), nil)
 	if err != nil {
 		return err
 	}
@@ -39,7 +50,12 @@ func (c *Client) DeleteCreditCard(id string) error {
 // GetCreditCard func
 // Endpoint: GET /v1/vault/credit-cards/credit_card_id
 func (c *Client) GetCreditCard(id string) (*CreditCard, error) {
-	req, err := c.NewRequest("GET", fmt.Sprintf("%s/v1/vault/credit-cards/%s", c.APIBase, id), nil)
+	return c.GetCreditCardContext(context.Background(), id)
+}
+
+// GetCreditCardContext is like GetCreditCard but uses ctx for the request
+func (c *Client) GetCreditCardContext(ctx context.Context, id string) (*CreditCard, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s/v1/vault/credit-cards/%s", c.APIBase, id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +72,11 @@ func (c *Client) GetCreditCard(id string) (*CreditCard, error) {
 // GetCreditCards func
 // Endpoint: GET /v1/vault/credit-cards
 func (c *Client) GetCreditCards(ccf *CreditCardsFilter) (*CreditCards, error) {
+	return c.GetCreditCardsContext(context.Background(), ccf)
+}
+
+// GetCreditCardsContext is like GetCreditCards but uses ctx for the request
+func (c *Client) GetCreditCardsContext(ctx context.Context, ccf *CreditCardsFilter) (*CreditCards, error) {
 	page := 1
 	if ccf != nil && ccf.Page > 0 {
 		page = ccf.Page
@@ -65,7 +86,7 @@ func (c *Client) GetCreditCards(ccf *CreditCardsFilter) (*CreditCards, error) {
 		pageSize = ccf.PageSize
 	}
 
-	req, err := c.NewRequest("GET", fmt.Sprintf("%s/v1/vault/credit-cards?page=%d&page_size=%d", c.APIBase, page, pageSize), nil)
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s/v1/vault/credit-cards?page=%d&page_size=%d", c.APIBase, page, pageSize), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +103,12 @@ func (c *Client) GetCreditCards(ccf *CreditCardsFilter) (*CreditCards, error) {
 // PatchCreditCard func
 // Endpoint: PATCH /v1/vault/credit-cards/credit_card_id
 func (c *Client) PatchCreditCard(id string, ccf []CreditCardField) (*CreditCard, error) {
-	req, err := c.NewRequest("PATCH", fmt.Sprintf("%s/v1/vault/credit-cards/%s", c.APIBase, id), ccf)
+	return c.PatchCreditCardContext(context.Background(), id, ccf)
+}
+
+// PatchCreditCardContext is like PatchCreditCard but uses ctx for the request
+func (c *Client) PatchCreditCardContext(ctx context.Context, id string, ccf []CreditCardField) (*CreditCard, error) {
+	req, err := c.NewRequestContext(ctx, "PATCH", fmt.Sprintf("%s/v1/vault/credit-cards/%s", c.APIBase, id), ccf)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,232 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// MaxWebhookBodySize is the size in bytes above which WebhookHandler rejects a webhook
+// before verifying it. PayPal events weigh a few KB, the cap bounds the memory an
+// unauthenticated sender can make the handler buffer
+const MaxWebhookBodySize = 512 << 10
+
+type (
+	// WebhookSignatureVerifier verifies the signature of an incoming webhook.
+	// *WebhookVerifier implements it
+	WebhookSignatureVerifier interface {
+		VerifySignature(ctx context.Context, header http.Header, body []byte) error
+	}
+
+	// WebhookHandlerFunc handles a verified webhook event.
+	// resource is the result of event.DecodeResource.
+	// Returning an error makes PayPal deliver the event again later
+	WebhookHandlerFunc func(ctx context.Context, event *WebhookEvent, resource interface{}) error
+
+	// WebhookHandler is an http.Handler which verifies incoming webhooks,
+	// decodes their resource and dispatches them by event type.
+	//
+	// It answers 200 once the event is handled, or when no handler is registered for it,
+	// 400 when the body is not a webhook event, 401 when the signature is invalid,
+	// 413 when the body exceeds MaxWebhookBodySize and 500 when verification or the handler failed, so PayPal retries the delivery
+	WebhookHandler struct {
+		// OnError, if set, is called with every error causing a non 200 answer
+		OnError func(r *http.Request, err error)
+
+		verifier WebhookSignatureVerifier
+
+		mu       sync.RWMutex
+		handlers map[string]WebhookHandlerFunc
+		fallback WebhookHandlerFunc
+	}
+)
+
+// NewWebhookHandler returns a WebhookHandler verifying events with verifier
+func NewWebhookHandler(verifier WebhookSignatureVerifier) *WebhookHandler {
+	return &WebhookHandler{
+		verifier: verifier,
+		handlers: map[string]WebhookHandlerFunc{},
+	}
+}
+
+// HandleFunc registers fn for events of eventType, e.g. EventPaymentCaptureCompleted
+func (h *WebhookHandler) HandleFunc(eventType string, fn WebhookHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = fn
+}
+
+// HandleDefault registers fn for events without a handler of their own
+func (h *WebhookHandler) HandleDefault(fn WebhookHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fallback = fn
+}
+
+// OnCapture registers fn for a capture event, e.g. EventPaymentCaptureCompleted
+func (h *WebhookHandler) OnCapture(eventType string, fn func(ctx context.Context, event *WebhookEvent, capture *Capture) error) {
+	h.HandleFunc(eventType, func(ctx context.Context, event *WebhookEvent, resource interface{}) error {
+		capture, ok := resource.(*Capture)
+		if !ok {
+			return fmt.Errorf("paypal: %s event carries a %s resource, not a capture", eventType, event.ResourceType)
+		}
+		return fn(ctx, event, capture)
+	})
+}
+
+// OnRefund registers fn for a refund event, e.g. EventPaymentCaptureRefunded
+func (h *WebhookHandler) OnRefund(eventType string, fn func(ctx context.Context, event *WebhookEvent, refund *RefundResponse) error) {
+	h.HandleFunc(eventType, func(ctx context.Context, event *WebhookEvent, resource interface{}) error {
+		refund, ok := resource.(*RefundResponse)
+		if !ok {
+			return fmt.Errorf("paypal: %s event carries a %s resource, not a refund", eventType, event.ResourceType)
+		}
+		return fn(ctx, event, refund)
+	})
+}
+
+// OnAuthorization registers fn for an authorization event, e.g. EventPaymentAuthorizationCreated
+func (h *WebhookHandler) OnAuthorization(eventType string, fn func(ctx context.Context, event *WebhookEvent, auth *Authorization) error) {
+	h.HandleFunc(eventType, func(ctx context.Context, event *WebhookEvent, resource interface{}) error {
+		auth, ok := resource.(*Authorization)
+		if !ok {
+			return fmt.Errorf("paypal: %s event carries a %s resource, not an authorization", eventType, event.ResourceType)
+		}
+		return fn(ctx, event, auth)
+	})
+}
+
+// OnDispute registers fn for a dispute event, e.g. EventCustomerDisputeCreated
+func (h *WebhookHandler) OnDispute(eventType string, fn func(ctx context.Context, event *WebhookEvent, dispute *Dispute) error) {
+	h.HandleFunc(eventType, func(ctx context.Context, event *WebhookEvent, resource interface{}) error {
+		dispute, ok := resource.(*Dispute)
+		if !ok {
+			return fmt.Errorf("paypal: %s event carries a %s resource, not a dispute", eventType, event.ResourceType)
+		}
+		return fn(ctx, event, dispute)
+	})
+}
+
+// OnOrder registers fn for an order event, e.g. EventCheckoutOrderApproved
+func (h *WebhookHandler) OnOrder(eventType string, fn func(ctx context.Context, event *WebhookEvent, order *Order) error) {
+	h.HandleFunc(eventType, func(ctx context.Context, event *WebhookEvent, resource interface{}) error {
+		order, ok := resource.(*Order)
+		if !ok {
+			return fmt.Errorf("paypal: %s event carries a %s resource, not an order", eventType, event.ResourceType)
+		}
+		return fn(ctx, event, order)
+	})
+}
+
+// OnMerchantOnboarding registers fn for a merchant onboarding event, e.g. EventMerchantOnboardingCompleted
+func (h *WebhookHandler) OnMerchantOnboarding(eventType string, fn func(ctx context.Context, event *WebhookEvent, merchant *Resource) error) {
+	h.HandleFunc(eventType, func(ctx context.Context, event *WebhookEvent, resource interface{}) error {
+		merchant, ok := resource.(*Resource)
+		if !ok {
+			return fmt.Errorf("paypal: %s event carries a %s resource, not a merchant", eventType, event.ResourceType)
+		}
+		return fn(ctx, event, merchant)
+	})
+}
+
+// ServeHTTP verifies, decodes and dispatches a webhook event
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, http.StatusMethodNotAllowed, fmt.Errorf("paypal: webhook sent with method %s", r.Method))
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxWebhookBodySize))
+	if err != nil && len(body) == MaxWebhookBodySize {
+		h.fail(w, r, http.StatusRequestEntityTooLarge, fmt.Errorf("paypal: webhook body exceeds %d bytes", MaxWebhookBodySize))
+		return
+	}
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.verifier.VerifySignature(r.Context(), r.Header, body); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrInvalidWebhookSignature) || errors.Is(err, ErrWebhookCertNotAllowed) {
+			status = http.StatusUnauthorized
+		}
+		h.fail(w, r, status, err)
+		return
+	}
+
+	event := &WebhookEvent{}
+	if err = json.Unmarshal(body, event); err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	h.mu.RLock()
+	fn, ok := h.handlers[event.EventType]
+	if !ok {
+		fn = h.fallback
+	}
+	h.mu.RUnlock()
+
+	if fn == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	resource, err := event.DecodeResource()
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = fn(r.Context(), event, resource); err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// fail answers status and reports err to OnError
+func (h *WebhookHandler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
+
+// DecodeResource decodes the resource of the event into the struct matching its resource type:
+// *Capture, *RefundResponse, *Authorization, *Dispute, *Order, or *Resource for
+// merchant onboarding and any other type
+func (e *WebhookEvent) DecodeResource() (interface{}, error) {
+	var resource interface{}
+	switch {
+	case e.ResourceType == WHResourceTypeCapture:
+		resource = &Capture{}
+	case e.ResourceType == WHResourceTypeRefund:
+		resource = &RefundResponse{}
+	case e.ResourceType == WHResourceTypeAuthorization:
+		resource = &Authorization{}
+	case e.ResourceType == WHResourceTypeDispute || strings.HasPrefix(e.EventType, "CUSTOMER.DISPUTE."):
+		resource = &Dispute{}
+	case e.ResourceType == WHResourceTypeCheckout || e.ResourceType == WHResourceTypeOrder:
+		resource = &Order{}
+	default:
+		resource = &Resource{}
+	}
+
+	if len(e.Resource) == 0 {
+		return resource, nil
+	}
+	if err := json.Unmarshal(e.Resource, resource); err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
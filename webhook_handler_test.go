@@ -0,0 +1,87 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	v, sign := newTestWebhookSigner(t, "WH-1")
+
+	var captured *Capture
+	h := NewWebhookHandler(v)
+	h.OnCapture(EventPaymentCaptureCompleted, func(ctx context.Context, event *WebhookEvent, capture *Capture) error {
+		captured = capture
+		return nil
+	})
+	h.OnDispute(EventCustomerDisputeCreated, func(ctx context.Context, event *WebhookEvent, dispute *Dispute) error {
+		return errors.New("try again later")
+	})
+
+	serve := func(r *http.Request) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	capture := []byte(`{
+		"id": "WH-58D329510W468432D-8HN650336L201105X",
+		"create_time": "2019-02-14T21:50:07.940Z",
+		"resource_type": "capture",
+		"event_type": "PAYMENT.CAPTURE.COMPLETED",
+		"resource": {
+			"id": "42311647XV020574X",
+			"status": "COMPLETED",
+			"amount": {"currency_code": "USD", "value": "15.00"},
+			"final_capture": true
+		}
+	}`)
+	if code := serve(sign(capture)); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if captured == nil || captured.ID != "42311647XV020574X" || captured.Status != CaptureStatusCompleted || captured.Amount.Value != "15.00" {
+		t.Fatalf("unexpected capture %+v", captured)
+	}
+
+	r := sign(capture)
+	r.Header.Set(HeaderTransmissionID, "replayed")
+	if code := serve(r); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an invalid signature, got %d", code)
+	}
+
+	dispute := []byte(`{"id":"WH-2","resource_type":"dispute","event_type":"CUSTOMER.DISPUTE.CREATED","resource":{"dispute_id":"PP-D-1"}}`)
+	if code := serve(sign(dispute)); code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 when the handler fails, got %d", code)
+	}
+
+	large := append([]byte(`{"id":"WH-4","event_type":"PAYMENT.CAPTURE.COMPLETED","summary":"`), bytes.Repeat([]byte("a"), MaxWebhookBodySize)...)
+	if code := serve(sign(append(large, `"}`...))); code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for an oversized body, got %d", code)
+	}
+
+	unhandled := []byte(`{"id":"WH-3","resource_type":"refund","event_type":"PAYMENT.CAPTURE.REFUNDED","resource":{}}`)
+	if code := serve(sign(unhandled)); code != http.StatusOK {
+		t.Fatalf("expected unhandled events to be acknowledged, got %d", code)
+	}
+}
+
+func TestWebhookEventDecodeResource(t *testing.T) {
+	event := &WebhookEvent{
+		ResourceType: WHResourceTypeMerchantOnboarding,
+		EventType:    EventMerchantOnboardingCompleted,
+		Resource:     []byte(`{"partner_client_id":"AXGz","merchant_id":"ZU5J","tracking_id":"seller-42"}`),
+	}
+
+	resource, err := event.DecodeResource()
+	if err != nil {
+		t.Fatal(err)
+	}
+	merchant, ok := resource.(*Resource)
+	if !ok || merchant.MerchantID != "ZU5J" || merchant.TrackingID != "seller-42" {
+		t.Fatalf("unexpected resource %#v", resource)
+	}
+}
@@ -0,0 +1,301 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers sent by PayPal with every webhook
+const (
+	HeaderAuthAlgo         = "PAYPAL-AUTH-ALGO"
+	HeaderCertURL          = "PAYPAL-CERT-URL"
+	HeaderTransmissionID   = "PAYPAL-TRANSMISSION-ID"
+	HeaderTransmissionSig  = "PAYPAL-TRANSMISSION-SIG"
+	HeaderTransmissionTime = "PAYPAL-TRANSMISSION-TIME"
+)
+
+// DefaultWebhookCertHosts are the hosts PayPal serves webhook signing certificates from
+var DefaultWebhookCertHosts = []string{
+	"api.paypal.com",
+	"api-m.paypal.com",
+	"api.sandbox.paypal.com",
+	"api-m.sandbox.paypal.com",
+}
+
+var (
+	// ErrInvalidWebhookSignature is returned when the signature of a webhook does not match its content
+	ErrInvalidWebhookSignature = errors.New("paypal: invalid webhook signature")
+	// ErrWebhookCertNotAllowed is returned when PAYPAL-CERT-URL is not an https URL on an allowed host
+	ErrWebhookCertNotAllowed = errors.New("paypal: webhook certificate URL is not allowed")
+)
+
+type (
+	// CertFetcher fetches the PEM encoded certificate found at PAYPAL-CERT-URL
+	CertFetcher interface {
+		FetchCert(ctx context.Context, certURL string) ([]byte, error)
+	}
+
+	// CertFetcherFunc adapts a function to CertFetcher
+	CertFetcherFunc func(ctx context.Context, certURL string) ([]byte, error)
+
+	// HTTPCertFetcher downloads certificates with Client, or http.DefaultClient when nil
+	HTTPCertFetcher struct {
+		Client *http.Client
+	}
+
+	// WebhookVerifier verifies webhook signatures locally, without calling
+	// /v1/notifications/verify-webhook-signature.
+	//
+	// Certificates are fetched from PAYPAL-CERT-URL once and cached until they expire.
+	// A WebhookVerifier is safe for concurrent use
+	WebhookVerifier struct {
+		// WebhookID is the ID of the webhook, as returned when it was created
+		WebhookID string
+		// CertFetcher defaults to an HTTPCertFetcher
+		CertFetcher CertFetcher
+		// AllowedCertHosts defaults to DefaultWebhookCertHosts
+		AllowedCertHosts []string
+
+		mu       sync.Mutex
+		certs    map[string]*x509.Certificate
+		fetching map[string]*certFlight
+	}
+
+	// certFlight is a certificate fetch callers wait for
+	certFlight struct {
+		done chan struct{}
+		cert *x509.Certificate
+		err  error
+	}
+)
+
+// maxWebhookCerts caps the certificates a WebhookVerifier caches, PayPal signs
+// with a handful of them at a time
+const maxWebhookCerts = 16
+
+// FetchCert calls f(ctx, certURL)
+func (f CertFetcherFunc) FetchCert(ctx context.Context, certURL string) ([]byte, error) {
+	return f(ctx, certURL)
+}
+
+// FetchCert downloads the certificate at certURL
+func (f HTTPCertFetcher) FetchCert(ctx context.Context, certURL string) ([]byte, error) {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("paypal: unable to fetch webhook certificate %s: %s", certURL, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// NewWebhookVerifier returns a WebhookVerifier for the webhook with the given ID
+func NewWebhookVerifier(webhookID string) *WebhookVerifier {
+	return &WebhookVerifier{WebhookID: webhookID}
+}
+
+// Verify verifies the signature of a webhook received from PayPal.
+// The body of r is read and restored, so r can still be decoded afterwards
+func (v *WebhookVerifier) Verify(r *http.Request) error {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(r.Body); err != nil {
+			return err
+		}
+	}
+	// Restore the io.ReadCloser to its original state
+	r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+	return v.VerifySignature(r.Context(), r.Header, body)
+}
+
+// VerifySignature verifies the signature of a webhook given its headers and raw body
+func (v *WebhookVerifier) VerifySignature(ctx context.Context, header http.Header, body []byte) error {
+	if algo := header.Get(HeaderAuthAlgo); algo != "" && !strings.EqualFold(algo, "SHA256withRSA") {
+		return fmt.Errorf("paypal: unsupported webhook signature algorithm %s", algo)
+	}
+
+	transmissionID := header.Get(HeaderTransmissionID)
+	transmissionTime := header.Get(HeaderTransmissionTime)
+	if transmissionID == "" || transmissionTime == "" {
+		return ErrInvalidWebhookSignature
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(header.Get(HeaderTransmissionSig))
+	if err != nil || len(sig) == 0 {
+		return ErrInvalidWebhookSignature
+	}
+
+	cert, err := v.cert(ctx, header.Get(HeaderCertURL))
+	if err != nil {
+		return err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("paypal: webhook certificate does not hold an RSA key")
+	}
+
+	message := fmt.Sprintf("%s|%s|%s|%d", transmissionID, transmissionTime, v.WebhookID, crc32.ChecksumIEEE(body))
+	digest := sha256.Sum256([]byte(message))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return ErrInvalidWebhookSignature
+	}
+
+	return nil
+}
+
+// cert returns the certificate at certURL, from the cache when possible.
+// A single fetch runs at a time for a URL, concurrent callers wait for its result
+func (v *WebhookVerifier) cert(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	certURL, ok := v.normalize(certURL)
+	if !ok {
+		return nil, ErrWebhookCertNotAllowed
+	}
+
+	for {
+		v.mu.Lock()
+		cert, cached := v.certs[certURL]
+		if cached && time.Now().Before(cert.NotAfter) {
+			v.mu.Unlock()
+			return cert, nil
+		}
+		f, fetching := v.fetching[certURL]
+		if !fetching {
+			if v.fetching == nil {
+				v.fetching = map[string]*certFlight{}
+			}
+			f = &certFlight{done: make(chan struct{})}
+			v.fetching[certURL] = f
+		}
+		v.mu.Unlock()
+
+		if fetching {
+			select {
+			case <-f.done:
+				if ctx.Err() == nil && (errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded)) {
+					continue
+				}
+				return f.cert, f.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		func() {
+			defer func() {
+				v.mu.Lock()
+				delete(v.fetching, certURL)
+				if f.err == nil {
+					v.store(certURL, f.cert)
+				}
+				v.mu.Unlock()
+				close(f.done)
+			}()
+			f.cert, f.err = v.fetch(ctx, certURL)
+		}()
+		return f.cert, f.err
+	}
+}
+
+// fetch downloads and parses the certificate at certURL
+func (v *WebhookVerifier) fetch(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	fetcher := v.CertFetcher
+	if fetcher == nil {
+		fetcher = HTTPCertFetcher{}
+	}
+	data, err := fetcher.FetchCert(ctx, certURL)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("paypal: webhook certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, errors.New("paypal: webhook certificate is expired or not yet valid")
+	}
+	return cert, nil
+}
+
+// store caches cert for certURL, making room by dropping expired certificates,
+// or any other one when none expired. v.mu must be held
+func (v *WebhookVerifier) store(certURL string, cert *x509.Certificate) {
+	if v.certs == nil {
+		v.certs = map[string]*x509.Certificate{}
+	}
+	if _, ok := v.certs[certURL]; !ok && len(v.certs) >= maxWebhookCerts {
+		now := time.Now()
+		for u, c := range v.certs {
+			if now.After(c.NotAfter) {
+				delete(v.certs, u)
+			}
+		}
+		for u := range v.certs {
+			if len(v.certs) < maxWebhookCerts {
+				break
+			}
+			delete(v.certs, u)
+		}
+	}
+	v.certs[certURL] = cert
+}
+
+// normalize returns certURL with a lower case host, and whether it is an https
+// URL on one of the allowed hosts. URLs with credentials, a query or a fragment are
+// not allowed, as they would let a sender force new fetches of the same certificate
+func (v *WebhookVerifier) normalize(certURL string) (string, bool) {
+	if strings.ContainsAny(certURL, "?#") {
+		return "", false
+	}
+	u, err := url.Parse(certURL)
+	if err != nil || u.Scheme != "https" || u.User != nil {
+		return "", false
+	}
+	u.Host = strings.ToLower(u.Host)
+
+	hosts := v.AllowedCertHosts
+	if hosts == nil {
+		hosts = DefaultWebhookCertHosts
+	}
+	for _, h := range hosts {
+		if strings.EqualFold(u.Hostname(), h) {
+			return u.String(), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,147 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testCertURL = "https://api.sandbox.paypal.com/v1/notifications/certs/CERT-360caa42-fca2a594-test"
+
+// newTestWebhookSigner returns a verifier trusting a freshly generated certificate
+// and a function signing webhooks with the matching key
+func newTestWebhookSigner(t *testing.T, webhookID string) (*WebhookVerifier, func(body []byte) *http.Request) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "messageverificationcerts.paypal.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	fetches := 0
+	v := NewWebhookVerifier(webhookID)
+	v.CertFetcher = CertFetcherFunc(func(ctx context.Context, certURL string) ([]byte, error) {
+		fetches++
+		if fetches > 1 {
+			t.Errorf("expected certificate to be cached")
+		}
+		return certPEM, nil
+	})
+
+	sign := func(body []byte) *http.Request {
+		id, ts := "dd4d3a30-0f5f-11e9-b4a6-3d7b8a1b9e8a", "2019-01-05T08:06:50Z"
+		digest := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d", id, ts, webhookID, crc32.ChecksumIEEE(body))))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, _ := http.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+		r.Header.Set(HeaderAuthAlgo, "SHA256withRSA")
+		r.Header.Set(HeaderCertURL, testCertURL)
+		r.Header.Set(HeaderTransmissionID, id)
+		r.Header.Set(HeaderTransmissionTime, ts)
+		r.Header.Set(HeaderTransmissionSig, base64.StdEncoding.EncodeToString(sig))
+		return r
+	}
+
+	return v, sign
+}
+
+func TestWebhookVerifier(t *testing.T) {
+	v, sign := newTestWebhookSigner(t, "WH-1")
+	body := []byte(`{"id":"WH-EVENT-1","event_type":"PAYMENT.CAPTURE.COMPLETED"}`)
+
+	if err := v.Verify(sign(body)); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+
+	r, _ := http.NewRequest("POST", "/webhooks", bytes.NewReader([]byte(`{"id":"WH-EVENT-2"}`)))
+	r.Header = sign(body).Header
+	if err := v.Verify(r); err != ErrInvalidWebhookSignature {
+		t.Fatalf("expected tampered body to be rejected, got %v", err)
+	}
+
+	other := NewWebhookVerifier("WH-2")
+	other.CertFetcher = v.CertFetcher
+	other.certs = v.certs
+	if err := other.Verify(sign(body)); err != ErrInvalidWebhookSignature {
+		t.Fatalf("expected signature for another webhook to be rejected, got %v", err)
+	}
+
+	r = sign(body)
+	r.Header.Set(HeaderCertURL, "https://evil.example.com/cert.pem")
+	if err := v.Verify(r); err != ErrWebhookCertNotAllowed {
+		t.Fatalf("expected certificate host to be rejected, got %v", err)
+	}
+}
+
+func TestWebhookVerifierCertCache(t *testing.T) {
+	signer, _ := newTestWebhookSigner(t, "WH-1")
+	certPEM, _ := signer.CertFetcher.FetchCert(context.Background(), testCertURL)
+
+	var fetches int32
+	release := make(chan struct{})
+	v := NewWebhookVerifier("WH-1")
+	v.CertFetcher = CertFetcherFunc(func(ctx context.Context, certURL string) ([]byte, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return certPEM, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := v.cert(context.Background(), testCertURL); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if fetches != 1 {
+		t.Fatalf("expected a single fetch, got %d", fetches)
+	}
+
+	for _, u := range []string{testCertURL + "?v=1", testCertURL + "#1", "https://user@api.sandbox.paypal.com/v1/notifications/certs/CERT-1"} {
+		if _, err := v.cert(context.Background(), u); err != ErrWebhookCertNotAllowed {
+			t.Fatalf("expected %s to be rejected, got %v", u, err)
+		}
+	}
+
+	for i := 0; i < 3*maxWebhookCerts; i++ {
+		if _, err := v.cert(context.Background(), fmt.Sprintf("%s-%d", testCertURL, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(v.certs) > maxWebhookCerts {
+		t.Fatalf("expected at most %d cached certificates, got %d", maxWebhookCerts, len(v.certs))
+	}
+}
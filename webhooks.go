@@ -2,15 +2,24 @@ package paypal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 // VerifyWebhookSignature - Use this to verify the signature of a webhook recieved from paypal.
 // Endpoint: POST /v1/notifications/verify-webhook-signature
 func (c *Client) VerifyWebhookSignature(httpReq *http.Request, webhookID string) (*VerifyWebhookResponse, error) {
+	return c.VerifyWebhookSignatureContext(context.Background(), httpReq, webhookID)
+}
+
+// VerifyWebhookSignatureContext is like VerifyWebhookSignature but uses ctx for the verification request
+func (c *Client) VerifyWebhookSignatureContext(ctx context.Context, httpReq *http.Request, webhookID string) (*VerifyWebhookResponse, error) {
 	type verifyWebhookSignatureRequest struct {
 		AuthAlgo         string          `json:"auth_algo,omitempty"`
 		CertURL          string          `json:"cert_url,omitempty"`
@@ -41,7 +50,7 @@ func (c *Client) VerifyWebhookSignature(httpReq *http.Request, webhookID string)
 
 	response := &VerifyWebhookResponse{}
 
-	req, err := c.NewRequest("POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/verify-webhook-signature"), verifyRequest)
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/verify-webhook-signature"), verifyRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -52,3 +61,273 @@ func (c *Client) VerifyWebhookSignature(httpReq *http.Request, webhookID string)
 
 	return response, nil
 }
+
+type (
+	// Webhook - https://developer.paypal.com/docs/api/webhooks/v1/#definition-webhook
+	Webhook struct {
+		ID         string             `json:"id,omitempty"`
+		URL        string             `json:"url"`
+		EventTypes []WebhookEventType `json:"event_types"`
+		Links      []Link             `json:"links,omitempty"`
+	}
+
+	// WebhookEventType - https://developer.paypal.com/docs/api/webhooks/v1/#definition-event_type
+	// Use the name "*" to subscribe to all events
+	WebhookEventType struct {
+		Name             string   `json:"name"`
+		Description      string   `json:"description,omitempty"`
+		Status           string   `json:"status,omitempty"`
+		ResourceVersions []string `json:"resource_versions,omitempty"`
+	}
+
+	// ListWebhooksResponse GET /v1/notifications/webhooks
+	ListWebhooksResponse struct {
+		Webhooks []Webhook `json:"webhooks"`
+	}
+
+	// WebhookEventTypesResponse GET /v1/notifications/webhooks-event-types
+	WebhookEventTypesResponse struct {
+		EventTypes []WebhookEventType `json:"event_types"`
+	}
+
+	// ListWebhookEventsParams filters ListWebhookEvents.
+	// Zero values are not sent
+	ListWebhookEventsParams struct {
+		PageSize      int
+		StartTime     time.Time
+		EndTime       time.Time
+		TransactionID string
+		EventType     string
+	}
+
+	// ListWebhookEventsResponse GET /v1/notifications/webhooks-events
+	ListWebhookEventsResponse struct {
+		Events []WebhookEvent `json:"events"`
+		Count  int            `json:"count"`
+		Links  []Link         `json:"links,omitempty"`
+	}
+
+	// SimulateWebhookEventRequest - https://developer.paypal.com/docs/api/webhooks/v1/#simulate-event_post
+	// Set either WebhookID or URL
+	SimulateWebhookEventRequest struct {
+		WebhookID       string `json:"webhook_id,omitempty"`
+		URL             string `json:"url,omitempty"`
+		EventType       string `json:"event_type"`
+		ResourceVersion string `json:"resource_version,omitempty"`
+	}
+)
+
+// CreateWebhook subscribes webhookURL to the given event types
+// Endpoint: POST /v1/notifications/webhooks
+func (c *Client) CreateWebhook(webhookURL string, eventTypes ...string) (*Webhook, error) {
+	return c.CreateWebhookContext(context.Background(), webhookURL, eventTypes...)
+}
+
+// CreateWebhookContext is like CreateWebhook but uses ctx for the request
+func (c *Client) CreateWebhookContext(ctx context.Context, webhookURL string, eventTypes ...string) (*Webhook, error) {
+	wh := Webhook{URL: webhookURL}
+	for _, name := range eventTypes {
+		wh.EventTypes = append(wh.EventTypes, WebhookEventType{Name: name})
+	}
+
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks"), wh)
+	response := &Webhook{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// ListWebhooks lists the webhooks of the application
+// Endpoint: GET /v1/notifications/webhooks
+func (c *Client) ListWebhooks() (*ListWebhooksResponse, error) {
+	return c.ListWebhooksContext(context.Background())
+}
+
+// ListWebhooksContext is like ListWebhooks but uses ctx for the request
+func (c *Client) ListWebhooksContext(ctx context.Context) (*ListWebhooksResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks?anchor_type=APPLICATION"), nil)
+	response := &ListWebhooksResponse{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// GetWebhook shows details for a webhook, by ID
+// Endpoint: GET /v1/notifications/webhooks/ID
+func (c *Client) GetWebhook(webhookID string) (*Webhook, error) {
+	return c.GetWebhookContext(context.Background(), webhookID)
+}
+
+// GetWebhookContext is like GetWebhook but uses ctx for the request
+func (c *Client) GetWebhookContext(ctx context.Context, webhookID string) (*Webhook, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks/"+webhookID), nil)
+	response := &Webhook{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// UpdateWebhook replaces the url or the event types of a webhook, by ID.
+// Only the replace operation is supported, e.g. {Operation: "replace", Path: "/event_types", Value: []WebhookEventType{...}}
+// Endpoint: PATCH /v1/notifications/webhooks/ID
+func (c *Client) UpdateWebhook(webhookID string, fields []PaymentPatch) (*Webhook, error) {
+	return c.UpdateWebhookContext(context.Background(), webhookID, fields)
+}
+
+// UpdateWebhookContext is like UpdateWebhook but uses ctx for the request
+func (c *Client) UpdateWebhookContext(ctx context.Context, webhookID string, fields []PaymentPatch) (*Webhook, error) {
+	req, err := c.NewRequestContext(ctx, "PATCH", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks/"+webhookID), fields)
+	response := &Webhook{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// DeleteWebhook deletes a webhook, by ID
+// Endpoint: DELETE /v1/notifications/webhooks/ID
+func (c *Client) DeleteWebhook(webhookID string) error {
+	return c.DeleteWebhookContext(context.Background(), webhookID)
+}
+
+// DeleteWebhookContext is like DeleteWebhook but uses ctx for the request
+func (c *Client) DeleteWebhookContext(ctx context.Context, webhookID string) error {
+	req, err := c.NewRequestContext(ctx, "DELETE", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks/"+webhookID), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.SendWithAuth(req, nil)
+}
+
+// ListWebhookEventTypes lists all the event types a webhook can subscribe to
+// Endpoint: GET /v1/notifications/webhooks-event-types
+func (c *Client) ListWebhookEventTypes() (*WebhookEventTypesResponse, error) {
+	return c.ListWebhookEventTypesContext(context.Background())
+}
+
+// ListWebhookEventTypesContext is like ListWebhookEventTypes but uses ctx for the request
+func (c *Client) ListWebhookEventTypesContext(ctx context.Context) (*WebhookEventTypesResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks-event-types"), nil)
+	response := &WebhookEventTypesResponse{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// query returns the query string parameters of p, p may be nil
+func (p *ListWebhookEventsParams) query() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(p.PageSize))
+	}
+	if !p.StartTime.IsZero() {
+		q.Set("start_time", p.StartTime.UTC().Format(DateFormatNoDot))
+	}
+	if !p.EndTime.IsZero() {
+		q.Set("end_time", p.EndTime.UTC().Format(DateFormatNoDot))
+	}
+	if p.TransactionID != "" {
+		q.Set("transaction_id", p.TransactionID)
+	}
+	if p.EventType != "" {
+		q.Set("event_type", p.EventType)
+	}
+	return q
+}
+
+// ListWebhookEvents lists the events sent to the webhooks of the application
+// Endpoint: GET /v1/notifications/webhooks-events
+func (c *Client) ListWebhookEvents(params *ListWebhookEventsParams) (*ListWebhookEventsResponse, error) {
+	return c.ListWebhookEventsContext(context.Background(), params)
+}
+
+// ListWebhookEventsContext is like ListWebhookEvents but uses ctx for the request
+func (c *Client) ListWebhookEventsContext(ctx context.Context, params *ListWebhookEventsParams) (*ListWebhookEventsResponse, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks-events"), nil)
+	response := &ListWebhookEventsResponse{}
+	if err != nil {
+		return response, err
+	}
+
+	req.URL.RawQuery = params.query().Encode()
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// GetWebhookEvent shows details for an event notification, by ID
+// Endpoint: GET /v1/notifications/webhooks-events/ID
+func (c *Client) GetWebhookEvent(eventID string) (*WebhookEvent, error) {
+	return c.GetWebhookEventContext(context.Background(), eventID)
+}
+
+// GetWebhookEventContext is like GetWebhookEvent but uses ctx for the request
+func (c *Client) GetWebhookEventContext(ctx context.Context, eventID string) (*WebhookEvent, error) {
+	req, err := c.NewRequestContext(ctx, "GET", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks-events/"+eventID), nil)
+	response := &WebhookEvent{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// ResendWebhookEvent resends an event notification, by ID, to the given webhooks
+// or, when none are given, to all the webhooks subscribed to it
+// Endpoint: POST /v1/notifications/webhooks-events/ID/resend
+func (c *Client) ResendWebhookEvent(eventID string, webhookIDs ...string) (*WebhookEvent, error) {
+	return c.ResendWebhookEventContext(context.Background(), eventID, webhookIDs...)
+}
+
+// ResendWebhookEventContext is like ResendWebhookEvent but uses ctx for the request
+func (c *Client) ResendWebhookEventContext(ctx context.Context, eventID string, webhookIDs ...string) (*WebhookEvent, error) {
+	type resendRequest struct {
+		WebhookIDs []string `json:"webhook_ids,omitempty"`
+	}
+
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/webhooks-events/"+eventID+"/resend"), resendRequest{WebhookIDs: webhookIDs})
+	response := &WebhookEvent{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
+
+// SimulateWebhookEvent sends a sample event to a webhook, to test its handler
+// Endpoint: POST /v1/notifications/simulate-event
+func (c *Client) SimulateWebhookEvent(r SimulateWebhookEventRequest) (*WebhookEvent, error) {
+	return c.SimulateWebhookEventContext(context.Background(), r)
+}
+
+// SimulateWebhookEventContext is like SimulateWebhookEvent but uses ctx for the request
+func (c *Client) SimulateWebhookEventContext(ctx context.Context, r SimulateWebhookEventRequest) (*WebhookEvent, error) {
+	req, err := c.NewRequestContext(ctx, "POST", fmt.Sprintf("%s%s", c.APIBase, "/v1/notifications/simulate-event"), r)
+	response := &WebhookEvent{}
+	if err != nil {
+		return response, err
+	}
+
+	err = c.SendWithAuth(req, response)
+	return response, err
+}
@@ -0,0 +1,152 @@
+package paypal
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookManagement(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+
+		switch {
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/v1/notifications/webhooks" && r.Method == "GET":
+			w.Write([]byte(`{"webhooks":[{"id":"40Y916089Y8324740","url":"https://example.com/paypal","event_types":[{"name":"PAYMENT.CAPTURE.COMPLETED"}]}]}`))
+		case r.URL.Path == "/v1/notifications/webhooks-event-types":
+			w.Write([]byte(`{"event_types":[{"name":"PAYMENT.CAPTURE.COMPLETED","description":"A payment capture completes.","status":"ENABLED","resource_versions":["1.0","2.0"]}]}`))
+		default:
+			w.Write([]byte(`{"id":"40Y916089Y8324740","url":"https://example.com/paypal","event_types":[{"name":"PAYMENT.CAPTURE.COMPLETED"},{"name":"PAYMENT.CAPTURE.REFUNDED"}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	wh, err := c.CreateWebhook("https://example.com/paypal", EventPaymentCaptureCompleted, EventPaymentCaptureRefunded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wh.ID != "40Y916089Y8324740" || len(wh.EventTypes) != 2 {
+		t.Fatalf("unexpected webhook %+v", wh)
+	}
+
+	list, err := c.ListWebhooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Webhooks) != 1 || list.Webhooks[0].EventTypes[0].Name != EventPaymentCaptureCompleted {
+		t.Fatalf("unexpected webhooks %+v", list)
+	}
+
+	if _, err = c.GetWebhook(wh.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.UpdateWebhook(wh.ID, []PaymentPatch{{Operation: "replace", Path: "/url", Value: "https://example.com/paypal/v2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.DeleteWebhook(wh.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	types, err := c.ListWebhookEventTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(types.EventTypes) != 1 || types.EventTypes[0].Status != "ENABLED" || len(types.EventTypes[0].ResourceVersions) != 2 {
+		t.Fatalf("unexpected event types %+v", types)
+	}
+
+	expected := []string{
+		`POST /v1/notifications/webhooks {"url":"https://example.com/paypal","event_types":[{"name":"PAYMENT.CAPTURE.COMPLETED"},{"name":"PAYMENT.CAPTURE.REFUNDED"}]}`,
+		`GET /v1/notifications/webhooks?anchor_type=APPLICATION `,
+		`GET /v1/notifications/webhooks/40Y916089Y8324740 `,
+		`PATCH /v1/notifications/webhooks/40Y916089Y8324740 [{"op":"replace","path":"/url","value":"https://example.com/paypal/v2"}]`,
+		`DELETE /v1/notifications/webhooks/40Y916089Y8324740 `,
+		`GET /v1/notifications/webhooks-event-types `,
+	}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected %d calls, got %v", len(expected), calls)
+	}
+	for i, e := range expected {
+		if calls[i] != e {
+			t.Errorf("expected call %s, got %s", e, calls[i])
+		}
+	}
+}
+
+func TestWebhookEvents(t *testing.T) {
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+
+		if r.URL.Path == "/v1/notifications/webhooks-events" {
+			q := r.URL.Query()
+			if q.Get("page_size") != "10" || q.Get("start_time") != "2020-01-01T00:00:00Z" || q.Get("end_time") != "2020-01-02T00:00:00Z" ||
+				q.Get("transaction_id") != "5O190127TN364715T" || q.Get("event_type") != EventPaymentCaptureCompleted {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"events":[{"id":"WH-COC11055RA711503B-4YM959094A144403T","event_type":"PAYMENT.CAPTURE.COMPLETED","resource_type":"capture","create_time":"2020-01-01T10:00:00Z"}],"count":1}`))
+			return
+		}
+		w.Write([]byte(`{"id":"WH-COC11055RA711503B-4YM959094A144403T","event_type":"PAYMENT.CAPTURE.COMPLETED","resource_type":"capture","resource":{"id":"42311647XV020574X"}}`))
+	}))
+	defer ts.Close()
+
+	c, _ := NewClient("foo", "bar", ts.URL)
+	c.SetAccessToken("token")
+
+	events, err := c.ListWebhookEvents(&ListWebhookEventsParams{
+		PageSize:      10,
+		StartTime:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:       time.Date(2020, 1, 2, 1, 0, 0, 0, time.FixedZone("CET", 3600)),
+		TransactionID: "5O190127TN364715T",
+		EventType:     EventPaymentCaptureCompleted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events.Count != 1 || events.Events[0].ResourceType != "capture" || events.Events[0].CreateTime.IsZero() {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	event, err := c.GetWebhookEvent("WH-COC11055RA711503B-4YM959094A144403T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.EventType != EventPaymentCaptureCompleted || len(event.Resource) == 0 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if _, err = c.ResendWebhookEvent(event.ID, "40Y916089Y8324740"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.ResendWebhookEvent(event.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.SimulateWebhookEvent(SimulateWebhookEventRequest{WebhookID: "40Y916089Y8324740", EventType: EventPaymentCaptureCompleted})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`GET /v1/notifications/webhooks-events/WH-COC11055RA711503B-4YM959094A144403T `,
+		`POST /v1/notifications/webhooks-events/WH-COC11055RA711503B-4YM959094A144403T/resend {"webhook_ids":["40Y916089Y8324740"]}`,
+		`POST /v1/notifications/webhooks-events/WH-COC11055RA711503B-4YM959094A144403T/resend {}`,
+		`POST /v1/notifications/simulate-event {"webhook_id":"40Y916089Y8324740","event_type":"PAYMENT.CAPTURE.COMPLETED"}`,
+	}
+	for i, e := range expected {
+		if calls[i+1] != e {
+			t.Errorf("expected call %s, got %s", e, calls[i+1])
+		}
+	}
+}